@@ -8,7 +8,7 @@
 NAME
 ROWS
  N  R0000000
- E  r.5
+ L  r.5
 COLUMNS
     M0000001  'MARKER'                 'INTORG'
     x0        R0000000            -4   r.5                  4
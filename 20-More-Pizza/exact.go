@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// The largest number of slices the dynamic programming over the smallest
+// pizzas covers. It takes 8 bytes of memory per number of slices.
+const maxSmallSum = 1 << 23
+
+type (
+	exactSearch struct {
+		problem Problem
+
+		big    []int // Types of the pizzas left to the search, by decreasing slices
+		suffix []int // Slices of big[k:], for each k
+		chosen []bool
+
+		small    []int   // Types of the pizzas left to the dynamic programming
+		smallsum int     // Slices of all the small pizzas
+		last     []int32 // Small pizza that first makes each sum reachable, -1 if none does
+		below    []int32 // Largest sum reachable with small pizzas up to each sum
+
+		best      int   // Most slices found so far
+		bestbig   []int // Big pizzas ordered to get them
+		bestsmall int   // Slices of small pizzas ordered to get them
+	}
+)
+
+// Returns the order with the most slices, found exactly.
+//
+// The smallest pizzas, up to maxSmallSum slices in total, are left to a
+// dynamic programming computing every sum of slices they can make, as a bitset
+// shifted by each pizza in turn. A branch and bound then tries the other
+// pizzas, largest first: each node is completed with the best sum of the small
+// pizzas that fits, and dropped if even all the pizzas left cannot beat the
+// best order found. The search stops as soon as an order hits the maximum,
+// which on the datasets happens at once.
+func SolveExact(p Problem) Solution {
+	types := make([]int, len(p.Slices))
+	for i := range types {
+		types[i] = i
+	}
+	sort.SliceStable(types, func(a, b int) bool { return p.Slices[types[a]] < p.Slices[types[b]] })
+
+	s := &exactSearch{problem: p}
+
+	limit := min(p.Max, maxSmallSum)
+	k := 0
+	for ; k < len(types) && s.smallsum+p.Slices[types[k]] <= limit; k++ {
+		s.smallsum += p.Slices[types[k]]
+	}
+	s.small = types[:k]
+
+	for i := len(types) - 1; i >= k; i-- {
+		s.big = append(s.big, types[i])
+	}
+	s.suffix = make([]int, len(s.big)+1)
+	for i := len(s.big) - 1; i >= 0; i-- {
+		s.suffix[i] = s.suffix[i+1] + p.Slices[s.big[i]]
+	}
+	s.chosen = make([]bool, len(s.big))
+
+	s.reach()
+	s.search(0, 0)
+
+	var sol Solution
+	sol = append(sol, s.bestbig...)
+	for sum := s.bestsmall; sum > 0; sum -= p.Slices[s.small[s.last[sum]]] {
+		sol = append(sol, s.small[s.last[sum]])
+	}
+	sort.Ints(sol)
+
+	return sol
+}
+
+// Computes the sums of slices the small pizzas can make.
+func (s *exactSearch) reach() {
+	n := s.smallsum + 1
+
+	s.last = make([]int32, n)
+	for sum := range s.last {
+		s.last[sum] = -1
+	}
+
+	reachable := make([]uint64, (n+63)/64)
+	reachable[0] = 1
+
+	for k, i := range s.small {
+		w := s.problem.Slices[i]
+		ws, bs := w/64, uint(w%64)
+
+		// Shifting down from the top words only reads words not updated yet
+		for j := len(reachable) - 1; j >= ws; j-- {
+			shifted := reachable[j-ws] << bs
+			if bs > 0 && j-ws > 0 {
+				shifted |= reachable[j-ws-1] >> (64 - bs)
+			}
+
+			added := shifted &^ reachable[j]
+			if j == len(reachable)-1 && n%64 != 0 {
+				added &= 1<<uint(n%64) - 1
+			}
+
+			reachable[j] |= added
+			for ; added != 0; added &= added - 1 {
+				s.last[j*64+bits.TrailingZeros64(added)] = int32(k)
+			}
+		}
+	}
+
+	s.below = make([]int32, n)
+	for sum := range s.below {
+		if sum == 0 || s.last[sum] != -1 {
+			s.below[sum] = int32(sum)
+		} else {
+			s.below[sum] = s.below[sum-1]
+		}
+	}
+}
+
+// Tries ordering or not big pizza `k` on top of `sum` slices of the previous
+// ones.
+func (s *exactSearch) search(k, sum int) {
+	if s.best == s.problem.Max {
+		return
+	}
+
+	small := int(s.below[min(s.problem.Max-sum, s.smallsum)])
+	if sum+small > s.best {
+		s.best, s.bestsmall = sum+small, small
+
+		s.bestbig = s.bestbig[:0]
+		for i, chosen := range s.chosen {
+			if chosen {
+				s.bestbig = append(s.bestbig, s.big[i])
+			}
+		}
+	}
+
+	if k == len(s.big) || sum+s.suffix[k]+s.smallsum <= s.best {
+		return
+	}
+
+	if slices := s.problem.Slices[s.big[k]]; sum+slices <= s.problem.Max {
+		s.chosen[k] = true
+		s.search(k+1, sum+slices)
+		s.chosen[k] = false
+	}
+
+	s.search(k+1, sum)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Returns an order with at least (1 - eps) times the most slices possible.
+//
+// This is the value-rounding approximation scheme of Ibarra and Kim. A greedy
+// order, largest pizzas first, gives a lower bound LB of at least half the
+// best. Pizzas with at most eps·LB/2 slices are small: once the others are
+// chosen, they are added while they fit, which loses at most eps·LB/2 slices.
+// At most m = 2·Max/(eps·LB) large pizzas fit in an order, so their slices are
+// scaled down by K = eps·LB/2m, losing at most eps·LB/2 slices in all, and a
+// dynamic programming finds the fewest slices making each scaled sum. Scaled
+// sums are at most Max/K = O(m/eps), so with n pizzas the run takes
+// O(n·m/eps) time and n·m/eps bits of memory, where m <= min(n, 4/eps).
+func SolveFPTAS(p Problem, eps float64) Solution {
+	// Pizzas that fit on their own, largest first
+	var types []int
+	total := 0
+	for i, slices := range p.Slices {
+		if slices <= p.Max {
+			types = append(types, i)
+			total += slices
+		}
+	}
+	sort.SliceStable(types, func(a, b int) bool { return p.Slices[types[a]] > p.Slices[types[b]] })
+
+	if total <= p.Max {
+		sol := Solution(types)
+		sort.Ints(sol)
+		return sol
+	}
+
+	// The greedy order holds its first pizza, and the first one it cannot
+	// take is smaller, so it has more than Max/2 slices
+	lb := 0
+	for _, i := range types {
+		if lb+p.Slices[i] <= p.Max {
+			lb += p.Slices[i]
+		}
+	}
+
+	threshold := eps * float64(lb) / 2
+	var large, small []int
+	for _, i := range types {
+		if float64(p.Slices[i]) > threshold {
+			large = append(large, i)
+		} else {
+			small = append(small, i)
+		}
+	}
+
+	var sol Solution
+	sum := 0
+
+	if len(large) > 0 {
+		m := min(len(large), int(2*float64(p.Max)/(eps*float64(lb))))
+		k := eps * float64(lb) / float64(2*m)
+		top := int(float64(p.Max) / k)
+
+		scaled := make([]int, len(large))
+		for t, i := range large {
+			scaled[t] = int(float64(p.Slices[i]) / k)
+		}
+
+		// Fewest slices making each scaled sum, and whether each large pizza
+		// lowered them when it was added
+		fewest := make([]int, top+1)
+		for v := range fewest {
+			fewest[v] = math.MaxInt
+		}
+		fewest[0] = 0
+		lowered := make([][]uint64, len(large))
+
+		for t, i := range large {
+			lowered[t] = make([]uint64, top/64+1)
+			for v := top; v >= scaled[t]; v-- {
+				if prev := fewest[v-scaled[t]]; prev != math.MaxInt && prev+p.Slices[i] <= p.Max && prev+p.Slices[i] < fewest[v] {
+					fewest[v] = prev + p.Slices[i]
+					lowered[t][v/64] |= 1 << uint(v%64)
+				}
+			}
+		}
+
+		v := top
+		for fewest[v] == math.MaxInt {
+			v--
+		}
+		for t := len(large) - 1; t >= 0; t-- {
+			if lowered[t][v/64]&(1<<uint(v%64)) != 0 {
+				sol = append(sol, large[t])
+				sum += p.Slices[large[t]]
+				v -= scaled[t]
+			}
+		}
+	}
+
+	for _, i := range small {
+		if sum+p.Slices[i] <= p.Max {
+			sol = append(sol, i)
+			sum += p.Slices[i]
+		}
+	}
+	sort.Ints(sol)
+
+	return sol
+}
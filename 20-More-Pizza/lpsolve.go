@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A variable of the model written by WriteMPS
+var variablePattern = regexp.MustCompile(`^x(\d+)$`)
+
+// Reads the solution of the model written by WriteMPS from the output of
+// lp_solve, whose "Actual values of the variables:" section lists a variable
+// and its value on each line. Variables missing from the output are not
+// ordered.
+func ParseLPSolve(in *bufio.Reader, p Problem) (Solution, error) {
+	var sol Solution
+	found := false
+	invariables := false
+
+	for lineno := 1; ; lineno++ {
+		line, err := readLine(in)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to read solution: %w", err)
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.Contains(line, "infeasible"), strings.Contains(line, "unbounded"):
+			return nil, fmt.Errorf("line %d: %s", lineno, line)
+		case strings.HasPrefix(line, "Actual values of the variables"):
+			found, invariables = true, true
+			continue
+		case strings.HasPrefix(line, "Actual values of the constraints"), strings.HasPrefix(line, "Dual value"):
+			invariables = false
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if !invariables || len(fields) != 2 {
+			continue
+		}
+
+		match := variablePattern.FindStringSubmatch(fields[0])
+		if match == nil {
+			return nil, fmt.Errorf("line %d: unknown variable %q", lineno, fields[0])
+		}
+
+		i, _ := strconv.Atoi(match[1])
+		if i >= len(p.Slices) {
+			return nil, fmt.Errorf("line %d: pizza type %d does not exist", lineno, i)
+		}
+
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: value of %s: %w", lineno, fields[0], err)
+		}
+
+		if value >= 0.5 {
+			sol = append(sol, i)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no values of the variables found")
+	}
+
+	sort.Ints(sol)
+
+	return sol, sol.Check(p)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLPSolve(t *testing.T) {
+	p := Problem{Max: 17, Slices: []int{2, 5, 6, 8}}
+
+	tests := []struct {
+		name   string
+		output string
+		want   Solution
+		fails  bool
+	}{
+		{
+			name: "all variables",
+			output: `
+Value of objective function: -16.00000000
+
+Actual values of the variables:
+x0                              1
+x1                              0
+x2                              1
+x3                              1
+
+Actual values of the constraints:
+r.5                            16
+`,
+			want: Solution{0, 2, 3},
+		},
+		{
+			name: "nonzero variables only, with rounding",
+			output: `
+Actual values of the variables:
+x3                              1
+x2                   0.9999999999
+x0                              1
+
+Dual value
+`,
+			want: Solution{0, 2, 3},
+		},
+		{
+			name:   "infeasible",
+			output: "\nThis problem is infeasible\n",
+			fails:  true,
+		},
+		{
+			name:   "no variables",
+			output: "\nValue of objective function: 0\n",
+			fails:  true,
+		},
+		{
+			name:   "unknown pizza",
+			output: "Actual values of the variables:\nx4 1\n",
+			fails:  true,
+		},
+		{
+			name:   "too many slices",
+			output: "Actual values of the variables:\nx1 1\nx2 1\nx3 1\n",
+			fails:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sol, err := ParseLPSolve(bufio.NewReader(strings.NewReader(test.output)), p)
+			if test.fails {
+				if err == nil {
+					t.Errorf("got %v, want an error", sol)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sol, test.want) {
+				t.Errorf("got %v, want %v", sol, test.want)
+			}
+		})
+	}
+}
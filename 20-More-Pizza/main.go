@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: go run . <command> [flags] [problem files]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  solve  order the most slices for each problem (default all in/*.in)")
+	fmt.Fprintln(os.Stderr, "  mps    write the MPS model of each problem, e.g. a.mps for in/a_example.in")
+	fmt.Fprintln(os.Stderr, "  lpsol  turn the output of lp_solve on a model into a submission")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "solve":
+		solveMain(os.Args[2:])
+	case "mps":
+		mpsMain(os.Args[2:])
+	case "lpsol":
+		lpsolMain(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func solveMain(args []string) {
+	cmd := flag.NewFlagSet("solve", flag.ExitOnError)
+	eps := cmd.Float64("eps", 0, "approximate within this factor of the best (0 solves exactly)")
+	outdir := cmd.String("out", "out", "directory the submissions are written to")
+	cmd.Parse(args)
+
+	if *eps < 0 || *eps >= 1 {
+		log.Fatalln("eps must be in [0, 1)")
+	}
+
+	for _, filename := range inputs(cmd.Args()) {
+		p := loadProblem(filename)
+
+		var sol Solution
+		if *eps == 0 {
+			sol = SolveExact(*p)
+		} else {
+			sol = SolveFPTAS(*p, *eps)
+		}
+
+		writeSolution(*p, sol, filepath.Join(*outdir, outputName(filename)))
+	}
+}
+
+func mpsMain(args []string) {
+	cmd := flag.NewFlagSet("mps", flag.ExitOnError)
+	cmd.Parse(args)
+
+	for _, filename := range inputs(cmd.Args()) {
+		p := loadProblem(filename)
+
+		letter, _ := datasetName(filename)
+		model := letter + ".mps"
+
+		fd, err := os.Create(model)
+		if err != nil {
+			log.Fatalf("unable to create file: %v", err)
+		}
+
+		if err := p.WriteMPS(fd, title(filename)); err != nil {
+			log.Fatalf("write %s: %v", model, err)
+		}
+		if err := fd.Close(); err != nil {
+			log.Fatalf("write %s: %v", model, err)
+		}
+
+		log.Println("model of", filename, "written to", model)
+	}
+}
+
+func lpsolMain(args []string) {
+	cmd := flag.NewFlagSet("lpsol", flag.ExitOnError)
+	input := cmd.String("in", "", "problem file the model was written for")
+	outdir := cmd.String("out", "out", "directory the submission is written to")
+	cmd.Parse(args)
+
+	if *input == "" || cmd.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . lpsol -in <problem> [lp_solve output, default stdin]")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	p := loadProblem(*input)
+
+	fd := os.Stdin
+	if cmd.NArg() == 1 {
+		var err error
+		if fd, err = os.Open(cmd.Arg(0)); err != nil {
+			log.Fatalf("unable to open file: %v", err)
+		}
+		defer fd.Close()
+	}
+
+	sol, err := ParseLPSolve(bufio.NewReader(fd), *p)
+	if err != nil {
+		log.Fatalln("parse lp_solve output:", err)
+	}
+
+	writeSolution(*p, sol, filepath.Join(*outdir, outputName(*input)))
+}
+
+// Returns the given problem files, or all of them if none is given.
+func inputs(args []string) []string {
+	if len(args) > 0 {
+		return args
+	}
+
+	filenames, err := filepath.Glob("in/*.in")
+	if err != nil {
+		log.Fatalln("list problems:", err)
+	}
+
+	return filenames
+}
+
+func loadProblem(filename string) *Problem {
+	fd, err := os.Open(filename)
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+	defer fd.Close()
+
+	p, err := LoadProblem(bufio.NewReader(fd))
+	if err != nil {
+		log.Fatalf("parse problem %s: %v", filename, err)
+	}
+
+	return p
+}
+
+// Splits the name of a problem file into the letter of its dataset and the
+// description of it, e.g. "a" and "example" for "in/a_example.in".
+func datasetName(filename string) (string, string) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	letter, description, _ := strings.Cut(name, "_")
+
+	return letter, strings.ReplaceAll(description, "_", " ")
+}
+
+// Returns the name of the submission for a problem file, e.g. "a.out" for
+// "in/a_example.in".
+func outputName(filename string) string {
+	letter, _ := datasetName(filename)
+	return letter + ".out"
+}
+
+// Returns the title of the dataset of a problem file, e.g. "D - quite big" for
+// "in/d_quite_big.in".
+func title(filename string) string {
+	letter, description := datasetName(filename)
+	return fmt.Sprintf("%s - %s", strings.ToUpper(letter), description)
+}
+
+// Checks the solution and writes it to `filename`.
+func writeSolution(p Problem, sol Solution, filename string) {
+	if err := sol.Check(p); err != nil {
+		log.Fatalf("invalid solution for %s: %v", filename, err)
+	}
+
+	fd, err := os.Create(filename)
+	if err != nil {
+		log.Fatalf("unable to create file: %v", err)
+	}
+
+	if err := sol.Write(fd); err != nil {
+		log.Fatalf("write %s: %v", filename, err)
+	}
+	if err := fd.Close(); err != nil {
+		log.Fatalf("write %s: %v", filename, err)
+	}
+
+	log.Printf("%s: %d slices out of %d, %d pizza types", filename, sol.Score(p), p.Max, len(sol))
+}
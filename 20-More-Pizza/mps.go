@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Writes the problem as a binary knapsack in fixed MPS format, for external
+// solvers such as lp_solve:
+//
+//	Minimize   - s0 x0 - s1 x1 - ...
+//	Subject to   s0 x0 + s1 x1 + ... <= max
+//
+// where xi is a binary variable telling whether pizza type i is ordered, and si
+// its number of slices. MPS only minimises, hence the negated objective.
+func (p Problem) WriteMPS(w io.Writer, title string) error {
+	var sb strings.Builder
+
+	n := len(p.Slices)
+	sb.WriteString(fmt.Sprintf("* Problem:    Subset Sum: %s\n", title))
+	sb.WriteString("* Class:      MIP\n")
+	sb.WriteString("* Rows:       1\n")
+	sb.WriteString(fmt.Sprintf("* Columns:    %d (%d integer, %d binary)\n", n, n, n))
+	sb.WriteString(fmt.Sprintf("* Non-zeros:  %d\n", n))
+	sb.WriteString("* Format:     Fixed MPS\n")
+	sb.WriteString("*\n")
+	sb.WriteString("NAME\n")
+	sb.WriteString("ROWS\n")
+	sb.WriteString(" N  R0000000\n")
+	sb.WriteString(" L  r.5\n")
+
+	sb.WriteString("COLUMNS\n")
+	sb.WriteString("    M0000001  'MARKER'                 'INTORG'\n")
+	for i, slices := range p.Slices {
+		sb.WriteString(fmt.Sprintf("    %-8s  %-8s  %12d   %-8s  %12d\n", variable(i), "R0000000", -slices, "r.5", slices))
+	}
+	sb.WriteString("    M0000002  'MARKER'                 'INTEND'\n")
+
+	sb.WriteString("RHS\n")
+	sb.WriteString(fmt.Sprintf("    %-8s  %-8s  %12d\n", "RHS1", "r.5", p.Max))
+
+	sb.WriteString("BOUNDS\n")
+	for i := range p.Slices {
+		sb.WriteString(fmt.Sprintf(" UP %-8s  %-8s  %12d\n", "BND1", variable(i), 1))
+	}
+	sb.WriteString("ENDATA\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Returns the variable telling whether pizza type `i` is ordered.
+func variable(i int) string {
+	return fmt.Sprintf("x%d", i)
+}
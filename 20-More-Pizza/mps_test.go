@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The models next to the problems must be the ones WriteMPS writes.
+func TestWriteMPSDatasets(t *testing.T) {
+	filenames, _ := filepath.Glob("in/*.in")
+
+	for _, filename := range filenames {
+		p := loadProblem(filename)
+
+		var got bytes.Buffer
+		if err := p.WriteMPS(&got, title(filename)); err != nil {
+			t.Fatal(err)
+		}
+
+		letter, _ := datasetName(filename)
+		want, err := os.ReadFile(letter + ".mps")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("%s: model differs from %s.mps", filename, letter)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		filename, title, output string
+	}{
+		{"in/a_example.in", "A - example", "a.out"},
+		{"in/d_quite_big.in", "D - quite big", "d.out"},
+		{"e_also_big.in", "E - also big", "e.out"},
+	}
+
+	for _, test := range tests {
+		if got := title(test.filename); got != test.title {
+			t.Errorf("%s: got title %q, want %q", test.filename, got, test.title)
+		}
+		if got := outputName(test.filename); got != test.output {
+			t.Errorf("%s: got submission %q, want %q", test.filename, got, test.output)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	Problem struct {
+		Max    int   // Maximum number of slices to order
+		Slices []int // Number of slices of each type of pizza
+	}
+)
+
+func LoadProblem(in *bufio.Reader) (*Problem, error) {
+	p := &Problem{}
+
+	line, err := readLine(in)
+	if err != nil {
+		return p, fmt.Errorf("unable to read problem: %w", err)
+	}
+
+	header, err := atoi(line)
+	if err != nil || len(header) != 2 {
+		return p, fmt.Errorf("invalid header %q: want <max slices> <pizza types>", line)
+	}
+
+	N := header[1]
+	p.Max = header[0]
+
+	line, err = readLine(in)
+	if err != nil {
+		return p, fmt.Errorf("unable to read pizzas: %w", err)
+	}
+
+	p.Slices, err = atoi(line)
+	if err != nil {
+		return p, fmt.Errorf("unable to read pizzas: %w", err)
+	}
+
+	if len(p.Slices) != N {
+		return p, fmt.Errorf("got %d pizza types, want %d", len(p.Slices), N)
+	}
+
+	for i, slices := range p.Slices {
+		if slices <= 0 {
+			return p, fmt.Errorf("pizza #%d has %d slices", i, slices)
+		}
+	}
+
+	return p, nil
+}
+
+// Reads a line, the last one of the file possibly lacking its newline.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err == io.EOF && strings.TrimSpace(line) != "" {
+		err = nil
+	}
+
+	return line, err
+}
+
+func atoi(line string) ([]int, error) {
+	fields := strings.Fields(line)
+	values := make([]int, len(fields))
+
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
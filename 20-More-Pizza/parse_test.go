@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadProblem(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Problem
+		fails bool
+	}{
+		{"example", "17 4\n2 5 6 8\n", &Problem{Max: 17, Slices: []int{2, 5, 6, 8}}, false},
+		{"no final newline", "17 4\n2 5 6 8", &Problem{Max: 17, Slices: []int{2, 5, 6, 8}}, false},
+		{"missing pizzas", "17 4\n2 5 6\n", nil, true},
+		{"no pizzas line", "17 4\n", nil, true},
+		{"bad header", "17\n2 5 6 8\n", nil, true},
+		{"empty pizza", "17 2\n0 5\n", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := LoadProblem(bufio.NewReader(strings.NewReader(test.input)))
+			if test.fails {
+				if err == nil {
+					t.Errorf("got %+v, want an error", p)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(p, test.want) {
+				t.Errorf("got %+v, want %+v", p, test.want)
+			}
+		})
+	}
+}
+
+func TestSolutionCheckWrite(t *testing.T) {
+	p := Problem{Max: 17, Slices: []int{2, 5, 6, 8}}
+
+	tests := []struct {
+		sol   Solution
+		fails bool
+	}{
+		{Solution{0, 2, 3}, false},
+		{Solution{}, false},
+		{Solution{1, 2, 3}, true},
+		{Solution{2, 0}, true},
+		{Solution{0, 0}, true},
+		{Solution{4}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.sol.Check(p); (err != nil) != test.fails {
+			t.Errorf("%v: got error %v", test.sol, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (Solution{0, 2, 3}).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "3\n0 2 3\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
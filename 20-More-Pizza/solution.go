@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Types of pizza to order, in increasing order
+	Solution []int
+)
+
+// Returns the number of slices ordered.
+func (s Solution) Score(p Problem) int {
+	score := 0
+	for _, i := range s {
+		score += p.Slices[i]
+	}
+
+	return score
+}
+
+// Returns an error if the solution is not a valid submission for the problem.
+func (s Solution) Check(p Problem) error {
+	for k, i := range s {
+		if i < 0 || i >= len(p.Slices) {
+			return fmt.Errorf("pizza type %d does not exist", i)
+		}
+
+		if k > 0 && i <= s[k-1] {
+			return fmt.Errorf("pizza type %d ordered after %d", i, s[k-1])
+		}
+	}
+
+	if score := s.Score(p); score > p.Max {
+		return fmt.Errorf("%d slices ordered, at most %d allowed", score, p.Max)
+	}
+
+	return nil
+}
+
+func (s Solution) Write(w io.Writer) error {
+	types := make([]string, len(s))
+	for k, i := range s {
+		types[k] = strconv.Itoa(i)
+	}
+
+	_, err := fmt.Fprintf(w, "%d\n%s\n", len(s), strings.Join(types, " "))
+	return err
+}
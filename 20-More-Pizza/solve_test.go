@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Returns the most slices that can be ordered, trying every order.
+func bruteForce(p Problem) int {
+	best := 0
+	for mask := 0; mask < 1<<len(p.Slices); mask++ {
+		sum := 0
+		for i, slices := range p.Slices {
+			if mask&(1<<i) != 0 {
+				sum += slices
+			}
+		}
+		if sum <= p.Max && sum > best {
+			best = sum
+		}
+	}
+
+	return best
+}
+
+func randomProblem(rng *rand.Rand) Problem {
+	p := Problem{Slices: make([]int, 1+rng.Intn(14))}
+
+	total := 0
+	for i := range p.Slices {
+		p.Slices[i] = 1 + rng.Intn(60)
+		total += p.Slices[i]
+	}
+	p.Max = rng.Intn(total + 10)
+
+	return p
+}
+
+func TestSolveExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 500; n++ {
+		p := randomProblem(rng)
+
+		sol := SolveExact(p)
+		if err := sol.Check(p); err != nil {
+			t.Fatalf("%+v: %v", p, err)
+		}
+		if got, want := sol.Score(p), bruteForce(p); got != want {
+			t.Fatalf("%+v: got %d slices, want %d", p, got, want)
+		}
+	}
+}
+
+func TestSolveFPTAS(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, eps := range []float64{0.01, 0.1, 0.5} {
+		for n := 0; n < 200; n++ {
+			p := randomProblem(rng)
+
+			sol := SolveFPTAS(p, eps)
+			if err := sol.Check(p); err != nil {
+				t.Fatalf("%+v: %v", p, err)
+			}
+			if got, want := sol.Score(p), bruteForce(p); float64(got) < (1-eps)*float64(want) {
+				t.Fatalf("eps %g, %+v: got %d slices, want at least %g", eps, p, got, (1-eps)*float64(want))
+			}
+		}
+	}
+}
+
+// The approximation must stay fast on the big datasets, which can be filled up.
+func TestSolveFPTASDatasets(t *testing.T) {
+	for _, filename := range []string{"in/d_quite_big.in", "in/e_also_big.in"} {
+		fd, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := LoadProblem(bufio.NewReader(fd))
+		fd.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+
+		for _, eps := range []float64{0.01, 0.1, 0.5} {
+			start := time.Now()
+			sol := SolveFPTAS(*p, eps)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("%s, eps %g: took %v", filename, eps, elapsed)
+			}
+
+			if err := sol.Check(*p); err != nil {
+				t.Fatalf("%s, eps %g: %v", filename, eps, err)
+			}
+			if got := sol.Score(*p); float64(got) < (1-eps)*float64(p.Max) {
+				t.Errorf("%s, eps %g: got %d slices, want at least %g", filename, eps, got, (1-eps)*float64(p.Max))
+			}
+		}
+	}
+}
+
+func TestSolveDatasets(t *testing.T) {
+	filenames, _ := filepath.Glob("in/*.in")
+	if len(filenames) == 0 {
+		t.Fatal("no datasets in in/")
+	}
+
+	for _, filename := range filenames {
+		fd, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := LoadProblem(bufio.NewReader(fd))
+		fd.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+
+		sol := SolveExact(*p)
+		if err := sol.Check(*p); err != nil {
+			t.Errorf("%s: %v", filename, err)
+		}
+
+		// Only the example cannot be filled up
+		want := p.Max
+		if filename == "in/a_example.in" {
+			want = 16
+		}
+		if score := sol.Score(*p); score != want {
+			t.Errorf("%s: got %d slices, want %d", filename, score, want)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 #!/usr/bin/env bash
-# The proposed problem can be viewed as a subset-sum optimization problem.
-# There are many subset-sum solvers out there. Also, one could easily write
-# an FPTAS for subset-sum in case an exact solution is taking too much time.
+# The proposed problem can be viewed as a subset-sum optimization problem,
+# which `go run . solve` solves exactly (or approximately, with -eps).
 # However, one could rewrite this as an ILP problem and throw it to any
 # linear solver, e.g. lp_solve (http://lpsolve.sourceforge.net/5.5/).
 #
@@ -15,47 +14,31 @@
 # the optimization problem is
 #
 # ```
-# Maximize 
+# Maximize
 #   2*x0 + 5*x1 + 6*x2 + 8*x3
 # Subject to
 #   2*x0 + 5*x1 + 6*x2 + 8*x3 <= 17
 # Binaries
 #   x0  x1  x2  x3
 # ```
+#
+# `go run . mps` writes these models, and `go run . lpsol` turns the output of
+# lp_solve into a checked submission.
 
-echo '[*] Solving a.mps'
-lp_solve -mps a.mps | tee a.sol.tmp
-rm -f out/a.out
-cat a.sol.tmp | grep -c -E "^x(\d*).*1$" >> out/a.out
-cat a.sol.tmp | sed -E "/^x(\d*).*1$/!d" | cut -c2- | cut -d ' ' -f 1 | tr '\n' ' ' >> out/a.out
-rm -f a.sol.tmp
+set -e
 
-echo '[*] Solving b.mps'
-lp_solve -mps b.mps | tee b.sol.tmp
-rm -f out/b.out
-cat b.sol.tmp | grep -c -E "^x(\d*).*1$" >> out/b.out
-cat b.sol.tmp | sed -E "/^x(\d*).*1$/!d" | cut -c2- | cut -d ' ' -f 1 | tr '\n' ' ' >> out/b.out
-rm -f b.sol.tmp
+go build -o more-pizza .
 
-echo '[*] Solving c.mps'
-lp_solve -mps c.mps | tee c.sol.tmp
-rm -f out/c.out
-cat c.sol.tmp | grep -c -E "^x(\d*).*1$" >> out/c.out
-cat c.sol.tmp | sed -E "/^x(\d*).*1$/!d" | cut -c2- | cut -d ' ' -f 1 | tr '\n' ' ' >> out/c.out
-rm -f c.sol.tmp
+for problem in in/*.in; do
+    letter=$(basename "$problem" | cut -c1)
 
-echo '[*] Solving d.mps'
-lp_solve -mps d.mps | tee d.sol.tmp
-rm -f out/d.out
-cat d.sol.tmp | grep -c -E "^x(\d*).*1$" >> out/d.out
-cat d.sol.tmp | sed -E "/^x(\d*).*1$/!d" | cut -c2- | cut -d ' ' -f 1 | tr '\n' ' ' >> out/d.out
-rm -f d.sol.tmp
+    echo "[*] Solving $letter.mps"
+    ./more-pizza mps "$problem"
+    lp_solve -mps "$letter.mps" | tee "$letter.sol.tmp"
+    ./more-pizza lpsol -in "$problem" "$letter.sol.tmp"
+    rm -f "$letter.sol.tmp"
+done
 
-echo '[*] Solving e.mps'
-lp_solve -mps e.mps | tee e.sol.tmp
-rm -f out/e.out
-cat e.sol.tmp | grep -c -E "^x(\d*).*1$" >> out/e.out
-cat e.sol.tmp | sed -E "/^x(\d*).*1$/!d" | cut -c2- | cut -d ' ' -f 1 | tr '\n' ' ' >> out/e.out
-rm -f e.sol.tmp
+rm -f more-pizza
 
 echo '[+] All done'
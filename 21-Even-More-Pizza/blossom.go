@@ -0,0 +1,588 @@
+package main
+
+import (
+	"context"
+)
+
+// An edge of weight w between vertices i and j.
+type weightedEdge struct {
+	i, j, w int
+}
+
+// State of Edmonds' blossom algorithm for maximum-weight matching, in the
+// primal-dual form described by Galil, "Efficient algorithms for finding
+// maximum matching in graphs" (1986).
+//
+// Edge k has endpoints 2k and 2k+1, so that p^1 is the other end of endpoint
+// p. Blossoms are numbered from n on, vertices being trivial blossoms. Vertex
+// duals are stored doubled, so that they stay integers.
+type blossomMatcher struct {
+	n     int
+	edges []weightedEdge
+	neigh [][]int // Remote endpoints of the edges of each vertex
+
+	mate     []int // Remote endpoint of the matched edge of each vertex, -1 if single
+	label    []int // 0 if free, 1 for S, 2 for T, with 4 set by scanBlossom
+	labelend []int // Endpoint through which each blossom got its label, -1 if none
+
+	inblossom []int // Top-level blossom containing each vertex
+	parent    []int // Blossom immediately containing each blossom, -1 if top-level
+	childs    [][]int
+	base      []int
+	endps     [][]int // Endpoints of the edges connecting consecutive children
+
+	bestedge   []int   // Least-slack edge to an S-blossom, -1 if none
+	bestedges  [][]int // Least-slack edges to each neighbouring S-blossom, of S-blossoms
+	bestedgeto []int   // Scratch space of addBlossom
+	unused     []int   // Blossom numbers not in use
+
+	dual    []int
+	allowed []int // Stage in which each edge was found tight
+	stage   int
+	queue   []int // S-vertices whose edges have not been scanned
+}
+
+// Returns a maximum-weight matching of the graph with `n` vertices and the
+// given edges, as the mate of each vertex (-1 if unmatched). Weights must be
+// positive. If `ctx` is cancelled, the matching found so far is returned.
+func maxWeightMatching(ctx context.Context, n int, edges []weightedEdge) []int {
+	m := &blossomMatcher{
+		n:          n,
+		edges:      edges,
+		neigh:      make([][]int, n),
+		mate:       make([]int, n),
+		label:      make([]int, 2*n),
+		labelend:   make([]int, 2*n),
+		inblossom:  make([]int, n),
+		parent:     make([]int, 2*n),
+		childs:     make([][]int, 2*n),
+		base:       make([]int, 2*n),
+		endps:      make([][]int, 2*n),
+		bestedge:   make([]int, 2*n),
+		bestedges:  make([][]int, 2*n),
+		bestedgeto: make([]int, 2*n),
+		dual:       make([]int, 2*n),
+		allowed:    make([]int, len(edges)),
+	}
+
+	maxweight := 0
+	for k, e := range edges {
+		m.neigh[e.i] = append(m.neigh[e.i], 2*k+1)
+		m.neigh[e.j] = append(m.neigh[e.j], 2*k)
+		maxweight = max(maxweight, e.w)
+	}
+
+	for v := 0; v < n; v++ {
+		m.mate[v] = -1
+		m.inblossom[v] = v
+		m.base[v] = v
+		m.dual[v] = maxweight
+	}
+	for b := 0; b < 2*n; b++ {
+		m.labelend[b] = -1
+		m.parent[b] = -1
+		m.bestedge[b] = -1
+		m.bestedgeto[b] = -1
+		if b >= n {
+			m.base[b] = -1
+			m.unused = append(m.unused, b)
+		}
+	}
+	for k := range m.allowed {
+		m.allowed[k] = -1
+	}
+
+	for m.stage = 0; m.stage < n && ctx.Err() == nil; m.stage++ {
+		if !m.augment() {
+			break
+		}
+	}
+
+	mate := make([]int, n)
+	for v, p := range m.mate {
+		mate[v] = -1
+		if p != -1 {
+			mate[v] = m.endpoint(p)
+		}
+	}
+
+	return mate
+}
+
+// Returns the vertex of endpoint `p`.
+func (m *blossomMatcher) endpoint(p int) int {
+	if p&1 == 0 {
+		return m.edges[p/2].i
+	}
+	return m.edges[p/2].j
+}
+
+// Returns the slack of edge `k`, doubled.
+func (m *blossomMatcher) slack(k int) int {
+	e := m.edges[k]
+	return m.dual[e.i] + m.dual[e.j] - 2*e.w
+}
+
+// Calls `visit` on the vertices of blossom `b`.
+func (m *blossomMatcher) leaves(b int, visit func(v int)) {
+	if b < m.n {
+		visit(b)
+		return
+	}
+	for _, child := range m.childs[b] {
+		m.leaves(child, visit)
+	}
+}
+
+// Returns the element of `list` at index `j`, counting from the end if j is
+// negative.
+func at(list []int, j int) int {
+	if j < 0 {
+		j += len(list)
+	}
+	return list[j]
+}
+
+// Returns the index of `x` in `list`.
+func indexOf(list []int, x int) int {
+	for j, y := range list {
+		if y == x {
+			return j
+		}
+	}
+	panic("Child not in blossom")
+}
+
+// Labels the top-level blossom of vertex `w` with `t`, reached through endpoint
+// `p`, and the mate of a T-blossom with S.
+func (m *blossomMatcher) assignLabel(w, t, p int) {
+	for {
+		b := m.inblossom[w]
+		m.label[w], m.label[b] = t, t
+		m.labelend[w], m.labelend[b] = p, p
+		m.bestedge[w], m.bestedge[b] = -1, -1
+
+		if t == 1 {
+			m.leaves(b, func(v int) { m.queue = append(m.queue, v) })
+			return
+		}
+
+		base := m.base[b]
+		w, t, p = m.endpoint(m.mate[base]), 1, m.mate[base]^1
+	}
+}
+
+// Traces back from S-vertices `v` and `w` to find either a new blossom, whose
+// base is returned, or an augmenting path, in which case -1 is returned.
+func (m *blossomMatcher) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inblossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.base[b]
+			break
+		}
+
+		path = append(path, b)
+		m.label[b] = 5
+		if m.labelend[b] == -1 {
+			v = -1
+		} else {
+			v = m.endpoint(m.labelend[b])
+			b = m.inblossom[v]
+			v = m.endpoint(m.labelend[b])
+		}
+
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+
+	for _, b := range path {
+		m.label[b] = 1
+	}
+
+	return base
+}
+
+// Makes a new blossom with base `base` out of the cycle closed by edge `k`.
+func (m *blossomMatcher) addBlossom(base, k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	bb, bv, bw := m.inblossom[base], m.inblossom[v], m.inblossom[w]
+
+	b := m.unused[len(m.unused)-1]
+	m.unused = m.unused[:len(m.unused)-1]
+	m.base[b] = base
+	m.parent[b] = -1
+	m.parent[bb] = b
+
+	var path, endps []int
+	for bv != bb {
+		m.parent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelend[bv])
+		v = m.endpoint(m.labelend[bv])
+		bv = m.inblossom[v]
+	}
+	path = append(path, bb)
+	for x, y := 0, len(path)-1; x < y; x, y = x+1, y-1 {
+		path[x], path[y] = path[y], path[x]
+	}
+	for x, y := 0, len(endps)-1; x < y; x, y = x+1, y-1 {
+		endps[x], endps[y] = endps[y], endps[x]
+	}
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.parent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelend[bw]^1)
+		w = m.endpoint(m.labelend[bw])
+		bw = m.inblossom[w]
+	}
+	m.childs[b], m.endps[b] = path, endps
+
+	m.label[b] = 1
+	m.labelend[b] = m.labelend[bb]
+	m.dual[b] = 0
+
+	m.leaves(b, func(v int) {
+		if m.label[m.inblossom[v]] == 2 {
+			// T-vertices become S-vertices, whose edges are to be scanned
+			m.queue = append(m.queue, v)
+		}
+		m.inblossom[v] = b
+	})
+
+	// Least-slack edges to neighbouring S-blossoms
+	var touched []int
+	consider := func(k int) {
+		i, j := m.edges[k].i, m.edges[k].j
+		if m.inblossom[j] == b {
+			i, j = j, i
+		}
+		bj := m.inblossom[j]
+		if bj != b && m.label[bj] == 1 && (m.bestedgeto[bj] == -1 || m.slack(k) < m.slack(m.bestedgeto[bj])) {
+			if m.bestedgeto[bj] == -1 {
+				touched = append(touched, bj)
+			}
+			m.bestedgeto[bj] = k
+		}
+	}
+	for _, bv := range path {
+		if m.bestedges[bv] == nil {
+			m.leaves(bv, func(v int) {
+				for _, p := range m.neigh[v] {
+					consider(p / 2)
+				}
+			})
+		} else {
+			for _, k := range m.bestedges[bv] {
+				consider(k)
+			}
+		}
+		m.bestedges[bv] = nil
+		m.bestedge[bv] = -1
+	}
+
+	best := make([]int, 0, len(touched))
+	for _, bj := range touched {
+		best = append(best, m.bestedgeto[bj])
+		m.bestedgeto[bj] = -1
+	}
+	m.bestedges[b] = best
+	m.bestedge[b] = -1
+	for _, k := range best {
+		if m.bestedge[b] == -1 || m.slack(k) < m.slack(m.bestedge[b]) {
+			m.bestedge[b] = k
+		}
+	}
+}
+
+// Expands blossom `b` into its children, relabelling them if `b` is a
+// T-blossom expanded during a stage.
+func (m *blossomMatcher) expandBlossom(b int, endstage bool) {
+	for _, s := range m.childs[b] {
+		m.parent[s] = -1
+		if s < m.n {
+			m.inblossom[s] = s
+		} else if endstage && m.dual[s] == 0 {
+			m.expandBlossom(s, endstage)
+		} else {
+			m.leaves(s, func(v int) { m.inblossom[v] = s })
+		}
+	}
+
+	if !endstage && m.label[b] == 2 {
+		childs, endps := m.childs[b], m.endps[b]
+
+		// Relabel the children on the even path from the entry child to the
+		// base as T and S in turn
+		entry := m.inblossom[m.endpoint(m.labelend[b]^1)]
+		j := indexOf(childs, entry)
+		jstep, trick := -1, 1
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep, trick = 1, 0
+		}
+
+		p := m.labelend[b]
+		for j != 0 {
+			m.label[m.endpoint(p^1)] = 0
+			m.label[m.endpoint(at(endps, j-trick)^trick^1)] = 0
+			m.assignLabel(m.endpoint(p^1), 2, p)
+			m.allowed[at(endps, j-trick)/2] = m.stage
+			j += jstep
+			p = at(endps, j-trick) ^ trick
+			m.allowed[p/2] = m.stage
+			j += jstep
+		}
+
+		bv := at(childs, j)
+		m.label[m.endpoint(p^1)], m.label[bv] = 2, 2
+		m.labelend[m.endpoint(p^1)], m.labelend[bv] = p, p
+		m.bestedge[bv] = -1
+		j += jstep
+
+		// The children on the odd path get their label back if reachable
+		for at(childs, j) != entry {
+			bv := at(childs, j)
+			j += jstep
+			if m.label[bv] == 1 {
+				continue
+			}
+
+			reached := -1
+			m.leaves(bv, func(v int) {
+				if reached == -1 && m.label[v] != 0 {
+					reached = v
+				}
+			})
+			if reached != -1 {
+				m.label[reached] = 0
+				m.label[m.endpoint(m.mate[m.base[bv]])] = 0
+				m.assignLabel(reached, 2, m.labelend[reached])
+			}
+		}
+	}
+
+	m.label[b], m.labelend[b] = -1, -1
+	m.childs[b], m.endps[b] = nil, nil
+	m.base[b] = -1
+	m.bestedges[b] = nil
+	m.bestedge[b] = -1
+	m.unused = append(m.unused, b)
+}
+
+// Swaps matched and unmatched edges along the even path from vertex `v` to the
+// base of blossom `b`, making v the new base.
+func (m *blossomMatcher) augmentBlossom(b, v int) {
+	t := v
+	for m.parent[t] != b {
+		t = m.parent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+
+	childs, endps := m.childs[b], m.endps[b]
+	i := indexOf(childs, t)
+	j := i
+	jstep, trick := -1, 1
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep, trick = 1, 0
+	}
+
+	for j != 0 {
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-trick) ^ trick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint(p))
+		}
+		j += jstep
+		t = at(childs, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint(p^1))
+		}
+		m.mate[m.endpoint(p)] = p ^ 1
+		m.mate[m.endpoint(p^1)] = p
+	}
+
+	m.childs[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+	m.endps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+	m.base[b] = m.base[m.childs[b][0]]
+}
+
+// Swaps matched and unmatched edges along the augmenting path through edge
+// `k`, which joins two S-vertices of different trees.
+func (m *blossomMatcher) augmentMatching(k int) {
+	for _, start := range [2][2]int{{m.edges[k].i, 2*k + 1}, {m.edges[k].j, 2 * k}} {
+		s, p := start[0], start[1]
+		for {
+			bs := m.inblossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelend[bs] == -1 {
+				break
+			}
+
+			t := m.endpoint(m.labelend[bs])
+			bt := m.inblossom[t]
+			s = m.endpoint(m.labelend[bt])
+			j := m.endpoint(m.labelend[bt] ^ 1)
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelend[bt]
+			p = m.labelend[bt] ^ 1
+		}
+	}
+}
+
+// Runs a stage of the algorithm: grows alternating trees from all single
+// vertices, adjusting the duals as needed, until an augmenting path is found.
+// Returns false if there is none, i.e., if the matching is optimal.
+func (m *blossomMatcher) augment() bool {
+	n := m.n
+	for b := 0; b < 2*n; b++ {
+		m.label[b] = 0
+		m.bestedge[b] = -1
+		if b >= n {
+			m.bestedges[b] = nil
+		}
+	}
+	m.queue = m.queue[:0]
+
+	for v := 0; v < n; v++ {
+		if m.mate[v] == -1 && m.label[m.inblossom[v]] == 0 {
+			m.assignLabel(v, 1, -1)
+		}
+	}
+
+	augmented := false
+	for {
+		for len(m.queue) > 0 && !augmented {
+			v := m.queue[len(m.queue)-1]
+			m.queue = m.queue[:len(m.queue)-1]
+
+			for _, p := range m.neigh[v] {
+				k, w := p/2, m.endpoint(p)
+				if m.inblossom[v] == m.inblossom[w] {
+					continue
+				}
+
+				kslack := 0
+				if m.allowed[k] != m.stage {
+					if kslack = m.slack(k); kslack <= 0 {
+						m.allowed[k] = m.stage
+					}
+				}
+
+				if m.allowed[k] == m.stage {
+					if m.label[m.inblossom[w]] == 0 {
+						m.assignLabel(w, 2, p^1)
+					} else if m.label[m.inblossom[w]] == 1 {
+						if base := m.scanBlossom(v, w); base >= 0 {
+							m.addBlossom(base, k)
+						} else {
+							m.augmentMatching(k)
+							augmented = true
+							break
+						}
+					} else if m.label[w] == 0 {
+						m.label[w] = 2
+						m.labelend[w] = p ^ 1
+					}
+				} else if m.label[m.inblossom[w]] == 1 {
+					if b := m.inblossom[v]; m.bestedge[b] == -1 || kslack < m.slack(m.bestedge[b]) {
+						m.bestedge[b] = k
+					}
+				} else if m.label[w] == 0 {
+					if m.bestedge[w] == -1 || kslack < m.slack(m.bestedge[w]) {
+						m.bestedge[w] = k
+					}
+				}
+			}
+		}
+
+		if augmented {
+			break
+		}
+
+		// No tight edge left to follow: change the duals by the largest
+		// amount keeping them feasible
+		deltatype, delta, deltaedge, deltablossom := 1, m.dual[0], -1, -1
+		for v := 0; v < n; v++ {
+			delta = min(delta, m.dual[v])
+		}
+		for v := 0; v < n; v++ {
+			if m.label[m.inblossom[v]] == 0 && m.bestedge[v] != -1 {
+				if d := m.slack(m.bestedge[v]); d < delta {
+					deltatype, delta, deltaedge = 2, d, m.bestedge[v]
+				}
+			}
+		}
+		for b := 0; b < 2*n; b++ {
+			if m.parent[b] == -1 && m.label[b] == 1 && m.bestedge[b] != -1 {
+				if d := m.slack(m.bestedge[b]) / 2; d < delta {
+					deltatype, delta, deltaedge = 3, d, m.bestedge[b]
+				}
+			}
+		}
+		for b := n; b < 2*n; b++ {
+			if m.base[b] >= 0 && m.parent[b] == -1 && m.label[b] == 2 && m.dual[b] < delta {
+				deltatype, delta, deltablossom = 4, m.dual[b], b
+			}
+		}
+
+		for v := 0; v < n; v++ {
+			switch m.label[m.inblossom[v]] {
+			case 1:
+				m.dual[v] -= delta
+			case 2:
+				m.dual[v] += delta
+			}
+		}
+		for b := n; b < 2*n; b++ {
+			if m.base[b] >= 0 && m.parent[b] == -1 {
+				switch m.label[b] {
+				case 1:
+					m.dual[b] += delta
+				case 2:
+					m.dual[b] -= delta
+				}
+			}
+		}
+
+		switch deltatype {
+		case 1:
+			// Single vertices reached a dual of 0: the matching is optimal
+			return false
+		case 2:
+			m.allowed[deltaedge] = m.stage
+			i, j := m.edges[deltaedge].i, m.edges[deltaedge].j
+			if m.label[m.inblossom[i]] == 0 {
+				i, j = j, i
+			}
+			m.queue = append(m.queue, i)
+		case 3:
+			m.allowed[deltaedge] = m.stage
+			m.queue = append(m.queue, m.edges[deltaedge].i)
+		case 4:
+			m.expandBlossom(deltablossom, false)
+		}
+	}
+
+	// S-blossoms whose dual dropped to 0 are expanded
+	for b := n; b < 2*n; b++ {
+		if m.parent[b] == -1 && m.base[b] >= 0 && m.label[b] == 1 && m.dual[b] == 0 {
+			m.expandBlossom(b, true)
+		}
+	}
+
+	return true
+}
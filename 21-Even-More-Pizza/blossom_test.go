@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// Returns the weight of a maximum-weight matching of the edges from the k-th
+// on, none touching the vertices in `used`, trying them all.
+func bruteForceMatching(edges []weightedEdge, k int, used []bool) int {
+	if k == len(edges) {
+		return 0
+	}
+
+	best := bruteForceMatching(edges, k+1, used)
+	if e := edges[k]; !used[e.i] && !used[e.j] {
+		used[e.i], used[e.j] = true, true
+		best = max(best, e.w+bruteForceMatching(edges, k+1, used))
+		used[e.i], used[e.j] = false, false
+	}
+
+	return best
+}
+
+func TestMaxWeightMatching(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for test := 0; test < 2000; test++ {
+		n := 2 + random.Intn(9)
+		var edges []weightedEdge
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if random.Intn(3) > 0 {
+					edges = append(edges, weightedEdge{i: i, j: j, w: 1 + random.Intn(1+test%20)})
+				}
+			}
+		}
+
+		mate := maxWeightMatching(context.Background(), n, edges)
+
+		total := 0
+		for _, e := range edges {
+			if mate[e.i] == e.j {
+				total += e.w
+			}
+		}
+		for v, w := range mate {
+			if w != -1 && mate[w] != v {
+				t.Fatalf("graph %v: vertex %d is matched with %d, which is matched with %d", edges, v, w, mate[w])
+			}
+		}
+
+		if want := bruteForceMatching(edges, 0, make([]bool, n)); total != want {
+			t.Fatalf("graph %v: got matching %v weighing %d, want %d", edges, mate, total, want)
+		}
+	}
+}
+
+// Once cancelled, the matching must still be one.
+func TestMaxWeightMatchingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	edges := []weightedEdge{{i: 0, j: 1, w: 2}, {i: 1, j: 2, w: 3}}
+	for v, w := range maxWeightMatching(ctx, 3, edges) {
+		if w != -1 {
+			t.Errorf("vertex %d is matched with %d before any stage", v, w)
+		}
+	}
+}
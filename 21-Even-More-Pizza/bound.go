@@ -0,0 +1,242 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Upper bounds of the score of a dataset.
+type Bound struct {
+	teams  int // Every team scores at most the squared ingredients of the largest pizzas
+	pizzas int // Every delivered ingredient is worth at most the ingredients of the largest team
+	exact  int // Best score, found by exhaustive search, or -1 if the dataset is too large
+
+	// Optimum of a linear relaxation, or -1 if the dataset is too large
+	relaxation int
+}
+
+// The largest number of ways of dealing pizzas that UpperBound searches
+// exhaustively.
+const exhaustiveDeals = 1000000
+
+// The largest simplex tableau, in entries, UpperBound solves the linear
+// relaxation with.
+const maxTableau = 20000000
+
+// Returns upper bounds of the score of the problem.
+//
+// A team of k people can get at most the ingredients of the k largest pizzas,
+// and no more than the ingredients of the whole dataset: let u(k) be that
+// number. The first bound serves the teams that would score the most if each
+// scored u(k)². The second notes that a team with n ingredients scores n², at
+// most u(K) times n for the largest team size K, and that the ingredients of
+// all the teams are at most those of the pizzas delivered: so the score is at
+// most u(K) times the ingredients of the largest pizzas that can be delivered.
+// The linear relaxation refines both, see relaxationBound.
+func (problem Problem) UpperBound() Bound {
+	sizes := make([]int, problem.M)
+	for pid, pizza := range problem.pizzas {
+		sizes[pid] = len(pizza.ingredientlist)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+	// Ingredients of the n largest pizzas
+	largest := make([]int, problem.M+1)
+	for n, size := range sizes {
+		largest[n+1] = largest[n] + size
+	}
+
+	u := make(map[int]float64)
+	value := make(map[int]float64)
+	for size := 2; size <= 4; size++ {
+		if size <= problem.M {
+			u[size] = float64(min(len(problem.ingredients), largest[size]))
+			value[size] = u[size] * u[size]
+		}
+	}
+
+	bound := Bound{
+		teams:      int(problem.bestTeams(value).expected),
+		exact:      -1,
+		relaxation: problem.relaxationBound(u),
+	}
+
+	maxsize := 0
+	for size := 2; size <= 4; size++ {
+		if problem.Teams(size) > 0 && size <= problem.M {
+			maxsize = size
+		}
+	}
+	delivered := min(problem.M, 2*problem.T2+3*problem.T3+4*problem.T4)
+	bound.pizzas = int(u[maxsize]) * largest[delivered]
+
+	// Every pizza goes to one of the teams or to nobody
+	teams := problem.T2 + problem.T3 + problem.T4
+	if math.Pow(float64(teams+1), float64(problem.M)) <= exhaustiveDeals {
+		bound.exact = problem.bestDeal()
+	}
+
+	return bound
+}
+
+// Returns the best upper bound.
+func (bound Bound) Value() int {
+	if bound.exact != -1 {
+		return bound.exact
+	}
+
+	value := min(bound.teams, bound.pizzas)
+	if bound.relaxation != -1 {
+		value = min(value, bound.relaxation)
+	}
+
+	return value
+}
+
+// Returns the optimum of a linear relaxation of the problem, or -1 if it is too
+// large to solve. A team of k people with n ingredients scores n² <= u(k)·n,
+// so the score is at most the sum of u(k) times the ingredients of each team.
+// Teams of the same size are interchangeable, so the program has, for each
+// team size k:
+//   - S_k, the number of teams served, at most T_k
+//   - X_ck, the number of pizzas of class c delivered to them, at most k·S_k
+//     in total, each class having no more pizzas than it has
+//   - Z_ik, the number of those teams having ingredient i, at most S_k and at
+//     most the pizzas with i they get, and at most u(k)·S_k in total
+//
+// and maximises the sum of u(k)·Z_ik.
+func (problem Problem) relaxationBound(u map[int]float64) int {
+	var sizes []int
+	for size := 2; size <= 4; size++ {
+		if problem.Teams(size) > 0 && size <= problem.M {
+			sizes = append(sizes, size)
+		}
+	}
+
+	C, I, K := len(problem.classes), len(problem.ingredients), len(sizes)
+	n := C*K + I*K + K
+	m := C + 2*I*K + 3*K + 1
+	if (m+1)*(n+m+1) > maxTableau {
+		return -1
+	}
+
+	x := func(c, k int) int { return c*K + k }
+	z := func(i, k int) int { return C*K + i*K + k }
+	s := func(k int) int { return C*K + I*K + k }
+
+	var A [][]float64
+	var b []float64
+	constraint := func(rhs float64) []float64 {
+		row := make([]float64, n)
+		A, b = append(A, row), append(b, rhs)
+		return row
+	}
+
+	for c, pids := range problem.classes {
+		row := constraint(float64(len(pids)))
+		for k := range sizes {
+			row[x(c, k)] = 1
+		}
+	}
+
+	for k, size := range sizes {
+		// Pizzas with each ingredient delivered to teams of this size
+		with := make([][]float64, I)
+		for i := range with {
+			with[i] = constraint(0)
+			with[i][z(i, k)] = 1
+		}
+		for c, pids := range problem.classes {
+			for _, i := range problem.pizzas[pids[0]].ingredientlist {
+				with[i][x(c, k)] = -1
+			}
+		}
+
+		for i := 0; i < I; i++ {
+			row := constraint(0)
+			row[z(i, k)], row[s(k)] = 1, -1
+		}
+
+		row := constraint(0)
+		for c := 0; c < C; c++ {
+			row[x(c, k)] = 1
+		}
+		row[s(k)] = -float64(size)
+
+		row = constraint(0)
+		for i := 0; i < I; i++ {
+			row[z(i, k)] = 1
+		}
+		row[s(k)] = -u[size]
+
+		row = constraint(float64(problem.Teams(size)))
+		row[s(k)] = 1
+	}
+
+	row := constraint(float64(problem.M))
+	for k, size := range sizes {
+		row[s(k)] = float64(size)
+	}
+
+	objective := make([]float64, n)
+	for k, size := range sizes {
+		for i := 0; i < I; i++ {
+			objective[z(i, k)] = u[size]
+		}
+	}
+
+	value, bounded := maximize(objective, A, b)
+	if !bounded {
+		panic("Unbounded relaxation")
+	}
+
+	return int(math.Floor(value + 1e-6))
+}
+
+// Returns the best score of the problem, trying every way of dealing the
+// pizzas to the teams.
+func (problem Problem) bestDeal() int {
+	var sizes []int
+	for size := 2; size <= 4; size++ {
+		for t := 0; t < problem.Teams(size); t++ {
+			sizes = append(sizes, size)
+		}
+	}
+
+	orders := make([]Order, len(sizes))
+	for t := range orders {
+		orders[t] = NewOrder()
+	}
+
+	best := 0
+
+	var deal func(pid int)
+	deal = func(pid int) {
+		if pid == problem.M {
+			score := 0
+			for t, order := range orders {
+				if n := len(order.pizzaids); n != 0 && n != sizes[t] {
+					return
+				}
+				score += order.score
+			}
+			best = max(best, score)
+			return
+		}
+
+		// Nobody gets the pizza
+		deal(pid + 1)
+
+		for t := range orders {
+			if len(orders[t].pizzaids) < sizes[t] {
+				orders[t] = problem.AddPizzaToOrder(pid, orders[t])
+				deal(pid + 1)
+				orders[t] = problem.RemovePizzaToOrder(pid, orders[t])
+			}
+		}
+	}
+
+	deal(0)
+
+	return best
+}
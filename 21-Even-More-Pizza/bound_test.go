@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestUpperBound(t *testing.T) {
+	// The best score of dataset A is known, so the bound is tight
+	if got := Parse("in/a.txt").UpperBound(); got.exact != 74 || got.Value() != 74 {
+		t.Errorf("dataset A: got bound %+v, want exactly 74", got)
+	}
+
+	// The relaxation must hold, and improve on the other bounds of A
+	if got := Parse("in/a.txt").UpperBound(); got.relaxation < 74 || got.relaxation >= min(got.teams, got.pizzas) {
+		t.Errorf("dataset A: got bound %+v, want a relaxation in [74, %d)", got, min(got.teams, got.pizzas))
+	}
+
+	// Bounds must hold for the solutions we have
+	for _, dataset := range []string{"b", "c", "d", "e"} {
+		problem := Parse("in/" + dataset + ".txt")
+		_, score := problem.Import("out/" + dataset + ".txt")
+
+		bound := problem.UpperBound()
+		if bound.teams < score || bound.pizzas < score || bound.relaxation != -1 && bound.relaxation < score {
+			t.Errorf("dataset %s: got bound %+v, but a solution scores %d", dataset, bound, score)
+		}
+
+		if bound.exact != -1 {
+			t.Errorf("dataset %s: exhaustive search should be out of reach", dataset)
+		}
+
+		if small := dataset == "b"; small != (bound.relaxation != -1) {
+			t.Errorf("dataset %s: got relaxation %d, want it solved only on B", dataset, bound.relaxation)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Builds orders one after the other, adding each time the remaining pizza that
+// brings the most new ingredients to the order (its marginal gain). Ingredients
+// count as much as their weight in Problem.weights, if any.
+//
+// Since adding pizzas to an order can only lower the gain of the others, the
+// gain of a pizza computed earlier is an upper bound of its current one, and so
+// is the weight of all its ingredients. Pizzas are thus streamed by decreasing
+// weight into a max-heap of upper bounds: only the pizzas whose bound beats the
+// best gain found so far are ever evaluated, which keeps each pick far below a
+// scan of all the remaining pizzas.
+//
+// Identical pizzas have the same gain, so the builder works on their classes:
+// a class is a single candidate, standing for as many pizzas as it has left,
+// and picking it delivers the one with the lowest ID.
+type GreedyBuilder struct {
+	problem  Problem
+	byweight []int   // First pizza of each class, by decreasing weight
+	weight   []int   // Weight of the ingredients of each pizza
+	avail    [][]int // IDs of the pizzas of each class not delivered yet
+	first    int     // Every class in byweight before this index has been delivered
+	left     int     // Number of pizzas not delivered yet
+}
+
+// A pizza in the heap of candidates for the order being built.
+type candidate struct {
+	pid   int // ID of the pizza
+	gain  int // Weight of the new ingredients it brings (an upper bound if stale)
+	stamp int // Size of the order when gain was computed, -1 if never computed
+}
+
+// A max-heap of candidates by gain.
+type candidateQueue []candidate
+
+func (q candidateQueue) Len() int { return len(q) }
+func (q candidateQueue) Less(i, j int) bool {
+	if q[i].gain != q[j].gain {
+		return q[i].gain > q[j].gain
+	}
+	return q[i].pid < q[j].pid
+}
+func (q candidateQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *candidateQueue) Push(x interface{}) { *q = append(*q, x.(candidate)) }
+func (q *candidateQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// Returns a builder of orders using only the pizzas in `rpizzaids`.
+func (problem Problem) NewGreedyBuilder(rpizzaids map[int]bool) *GreedyBuilder {
+	builder := &GreedyBuilder{
+		problem: problem,
+		weight:  make([]int, problem.M),
+		avail:   make([][]int, len(problem.classes)),
+	}
+
+	for c, pids := range problem.classes {
+		for _, pid := range pids {
+			builder.weight[pid] = problem.Weight(pid)
+			if rpizzaids[pid] {
+				builder.avail[c] = append(builder.avail[c], pid)
+			}
+		}
+
+		if len(builder.avail[c]) > 0 {
+			builder.byweight = append(builder.byweight, pids[0])
+			builder.left += len(builder.avail[c])
+		}
+	}
+
+	sort.Slice(builder.byweight, func(i, j int) bool {
+		a, b := builder.byweight[i], builder.byweight[j]
+		if builder.weight[a] != builder.weight[b] {
+			return builder.weight[a] > builder.weight[b]
+		}
+		return a < b
+	})
+
+	return builder
+}
+
+// Returns the number of pizzas not delivered yet.
+func (builder *GreedyBuilder) Remaining() int {
+	return builder.left
+}
+
+// Builds an order of `osize` pizzas, picking each time the pizza with the
+// highest marginal gain, and marks them as delivered.
+func (builder *GreedyBuilder) Order(osize int) Order {
+	if osize < 1 {
+		panic("An order must contain pizzas")
+	}
+
+	return builder.Extend(NewOrder(), osize)
+}
+
+// Adds pizzas to the order until it has `osize` of them, picking each time the
+// pizza with the highest marginal gain, and marks them as delivered.
+func (builder *GreedyBuilder) Extend(order Order, osize int) Order {
+	if osize-len(order.pizzaids) > builder.left {
+		panic("Not enough pizzas")
+	}
+
+	queue := &candidateQueue{}
+	next := builder.first
+
+	for len(order.pizzaids) < osize {
+		for {
+			// Stream in the pizzas that may beat the best candidate so far
+			for next < len(builder.byweight) {
+				pid := builder.byweight[next]
+				bound := builder.weight[pid]
+				if queue.Len() > 0 && bound <= (*queue)[0].gain {
+					break
+				}
+				if !builder.delivered(pid) {
+					heap.Push(queue, candidate{pid: pid, gain: bound, stamp: -1})
+				}
+				next++
+			}
+
+			best := heap.Pop(queue).(candidate)
+			if best.stamp == len(order.pizzaids) {
+				order = builder.problem.AddPizzaToOrder(builder.take(best.pid), order)
+
+				// The other pizzas of the class bring nothing new, but
+				// may still be needed to fill the order
+				if !builder.delivered(best.pid) {
+					heap.Push(queue, candidate{pid: best.pid, gain: 0, stamp: len(order.pizzaids)})
+				}
+				break
+			}
+
+			best.gain = builder.gain(best.pid, order)
+			best.stamp = len(order.pizzaids)
+			heap.Push(queue, best)
+		}
+	}
+
+	for builder.first < len(builder.byweight) && builder.delivered(builder.byweight[builder.first]) {
+		builder.first++
+	}
+
+	return order
+}
+
+// Returns true if every pizza identical to pizza `pid` has been delivered.
+func (builder *GreedyBuilder) delivered(pid int) bool {
+	return len(builder.avail[builder.problem.class[pid]]) == 0
+}
+
+// Delivers the pizza with the lowest ID among those identical to pizza `pid`,
+// and returns its ID.
+func (builder *GreedyBuilder) take(pid int) int {
+	c := builder.problem.class[pid]
+	taken := builder.avail[c][0]
+	builder.avail[c] = builder.avail[c][1:]
+	builder.left--
+
+	return taken
+}
+
+// Returns the weight of the ingredients of pizza `pid` that are not in the
+// order.
+func (builder *GreedyBuilder) gain(pid int, order Order) int {
+	gain := 0
+	for _, i := range builder.problem.pizzas[pid].ingredientlist {
+		if order.counts[i] == 0 {
+			gain += builder.problem.weight(i)
+		}
+	}
+
+	return gain
+}
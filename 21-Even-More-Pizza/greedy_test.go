@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+)
+
+// Returns true if the pizzas can be added one by one to an empty order, each
+// time bringing as many new ingredients as the best of the remaining pizzas.
+func isGreedy(builder *GreedyBuilder, pids []int, rpizzaids map[int]bool, order Order) bool {
+	if len(pids) == 0 {
+		return true
+	}
+
+	best := 0
+	for pid := range rpizzaids {
+		if gain := builder.gain(pid, order); gain > best {
+			best = gain
+		}
+	}
+
+	// Ties may have been broken either way
+	for k, pid := range pids {
+		if builder.gain(pid, order) != best {
+			continue
+		}
+
+		rest := append(append([]int{}, pids[:k]...), pids[k+1:]...)
+		delete(rpizzaids, pid)
+		ok := isGreedy(builder, rest, rpizzaids, builder.problem.AddPizzaToOrder(pid, order.Clone()))
+		rpizzaids[pid] = true
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestGreedyBuilderPicksBestGain(t *testing.T) {
+	for _, scheme := range []string{WeightingUniform, WeightingInverse, WeightingLog} {
+		t.Run(scheme, func(t *testing.T) {
+			problem := Parse("in/b.txt")
+			weights, err := problem.IngredientWeights(scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			problem.weights = weights
+
+			testGreedyBuilder(t, problem)
+		})
+	}
+}
+
+func testGreedyBuilder(t *testing.T, problem Problem) {
+	rpizzaids := make(map[int]bool)
+	for pid := 0; pid < problem.M; pid++ {
+		rpizzaids[pid] = true
+	}
+	builder := problem.NewGreedyBuilder(rpizzaids)
+
+	for osize := 2; builder.Remaining() >= osize; osize = osize%3 + 2 {
+		order := builder.Order(osize)
+		if len(order.pizzaids) != osize {
+			t.Fatalf("got an order of %d pizzas, want %d", len(order.pizzaids), osize)
+		}
+
+		pids := make([]int, 0, osize)
+		for pid := range order.pizzaids {
+			pids = append(pids, pid)
+		}
+
+		if !isGreedy(builder, pids, rpizzaids, NewOrder()) {
+			t.Fatalf("order %v does not pick pizzas by marginal gain", pids)
+		}
+
+		if want := scoreFromScratch(problem, order); order.score != want {
+			t.Fatalf("got score %d, want %d", order.score, want)
+		}
+
+		for _, pid := range pids {
+			delete(rpizzaids, pid)
+		}
+	}
+
+	if builder.Remaining() != len(rpizzaids) {
+		t.Errorf("got %d remaining pizzas, want %d", builder.Remaining(), len(rpizzaids))
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// State of the local search: the solution being improved, how many teams of
+// each size it serves and the pizzas it does not deliver.
+type localSearch struct {
+	problem  Problem
+	solution Solution
+	score    int
+	rng      *rand.Rand
+	served   map[int]int // Number of orders by team size
+	pool     []int       // IDs of the pizzas not delivered
+	inpool   []int       // Position of each pizza in pool, -1 if delivered
+}
+
+// Improves the solution by local search for `maxtime` seconds, or until `ctx`
+// is cancelled, accepting the moves that do not lower the score. The moves are
+// drawn from `seed`, so that runs can be reproduced.
+//
+// The neighbourhood is made of:
+//   - swapping a delivered pizza with one nobody ordered;
+//   - moving a pizza from an order to another, when the resulting team sizes
+//     are available;
+//   - swapping the best pair of pizzas between two orders;
+//   - spreading the heaviest ingredient an order has twice to another order;
+//   - serving an idle team with pizzas nobody ordered;
+//   - dropping an order and serving a team of any available size instead.
+func (problem Problem) Improve(ctx context.Context, solution Solution, maxtime float64, seed int64) (Solution, int) {
+	search := &localSearch{
+		problem:  problem,
+		solution: solution,
+		score:    solution.Score(),
+		rng:      rand.New(rand.NewSource(seed)),
+		inpool:   make([]int, problem.M),
+	}
+	search.served, _ = solution.Breakdown()
+
+	_, _, _, rpizzaids := problem.Remaining(solution)
+	for pid := range search.inpool {
+		search.inpool[pid] = -1
+		if rpizzaids[pid] {
+			search.release(pid)
+		}
+	}
+
+	snapshot := func() []byte { return problem.Format(search.solution) }
+
+	start := time.Now()
+	iteration := 0
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		iteration++
+		if iteration%100000 == 0 {
+			fmt.Println("Iteration", iteration, "Score:", search.score)
+		}
+
+		if len(search.solution) < 2 {
+			search.addOrder()
+			if len(search.solution) < 2 {
+				break
+			}
+			continue
+		}
+
+		switch r := search.rng.Intn(100); {
+		case r < 30:
+			search.swapUndelivered()
+		case r < 45:
+			search.movePizza()
+		case r < 75:
+			search.swapBestPair()
+		case r < 85:
+			search.spreadRare()
+		case r < 90:
+			search.addOrder()
+		default:
+			search.replaceOrder()
+		}
+
+		problem.tracker.Move(search.score, snapshot)
+	}
+
+	if score := search.solution.Score(); score != search.score {
+		panic(fmt.Sprintf("Score drifted to %d, should be %d", search.score, score))
+	}
+
+	return search.solution, search.score
+}
+
+// Returns the pizzas of the order in increasing ID, so that picking one at
+// random only depends on the seed.
+func pizzasOf(order Order) []int {
+	pids := make([]int, 0, len(order.pizzaids))
+	for pid := range order.pizzaids {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	return pids
+}
+
+// Puts a pizza back among the undelivered ones.
+func (search *localSearch) release(pid int) {
+	search.inpool[pid] = len(search.pool)
+	search.pool = append(search.pool, pid)
+}
+
+// Takes a pizza out of the undelivered ones.
+func (search *localSearch) take(pid int) {
+	k, last := search.inpool[pid], search.pool[len(search.pool)-1]
+	search.pool[k], search.inpool[last] = last, k
+	search.pool = search.pool[:len(search.pool)-1]
+	search.inpool[pid] = -1
+}
+
+// Returns the index of a random order.
+func (search *localSearch) randomOrder() int {
+	return search.rng.Intn(len(search.solution))
+}
+
+// Returns a random pizza of the order.
+func (search *localSearch) randomPizza(order Order) int {
+	pids := pizzasOf(order)
+	return pids[search.rng.Intn(len(pids))]
+}
+
+// Swaps a random pizza of a random order with a random undelivered one, unless
+// they are identical.
+func (search *localSearch) swapUndelivered() {
+	if len(search.pool) == 0 {
+		return
+	}
+
+	i := search.randomOrder()
+	out := search.randomPizza(search.solution[i])
+	in := search.pool[search.rng.Intn(len(search.pool))]
+	if search.problem.class[in] == search.problem.class[out] {
+		return
+	}
+
+	delta := search.problem.SwapDelta(search.solution[i], out, in)
+	if delta < 0 {
+		return
+	}
+
+	search.take(in)
+	search.release(out)
+	search.solution[i] = search.problem.RemovePizzaToOrder(out, search.solution[i])
+	search.solution[i] = search.problem.AddPizzaToOrder(in, search.solution[i])
+	search.score += delta
+}
+
+// Moves a random pizza from an order to another, which changes the size of
+// both teams. The move is only possible if teams of the new sizes are idle.
+func (search *localSearch) movePizza() {
+	i, j := search.randomOrder(), search.randomOrder()
+	if i == j {
+		return
+	}
+
+	from, to := len(search.solution[i].pizzaids), len(search.solution[j].pizzaids)
+	if from-1 < 2 || to+1 > 4 {
+		return
+	}
+
+	served := map[int]int{2: search.served[2], 3: search.served[3], 4: search.served[4]}
+	served[from]--
+	served[to]--
+	served[from-1]++
+	served[to+1]++
+	for size, n := range served {
+		if n > search.problem.Teams(size) {
+			return
+		}
+	}
+
+	pid := search.randomPizza(search.solution[i])
+	delta := search.problem.SwapDelta(search.solution[i], pid, -1) +
+		search.problem.SwapDelta(search.solution[j], -1, pid)
+	if delta < 0 {
+		return
+	}
+
+	search.solution[i] = search.problem.RemovePizzaToOrder(pid, search.solution[i])
+	search.solution[j] = search.problem.AddPizzaToOrder(pid, search.solution[j])
+	search.served = served
+	search.score += delta
+}
+
+// Tries every swap of different pizzas between two random orders, and performs
+// the best one.
+func (search *localSearch) swapBestPair() {
+	i, j := search.randomOrder(), search.randomOrder()
+	if i == j {
+		return
+	}
+
+	bestdelta, bestp, bestq := -1, -1, -1
+	for _, p := range pizzasOf(search.solution[i]) {
+		for _, q := range pizzasOf(search.solution[j]) {
+			if search.problem.class[p] == search.problem.class[q] {
+				continue
+			}
+
+			delta := search.problem.SwapDelta(search.solution[i], p, q) +
+				search.problem.SwapDelta(search.solution[j], q, p)
+			if delta > bestdelta {
+				bestdelta, bestp, bestq = delta, p, q
+			}
+		}
+	}
+
+	if bestdelta < 0 {
+		return
+	}
+
+	search.solution[i] = search.problem.RemovePizzaToOrder(bestp, search.solution[i])
+	search.solution[j] = search.problem.RemovePizzaToOrder(bestq, search.solution[j])
+	search.solution[i] = search.problem.AddPizzaToOrder(bestq, search.solution[i])
+	search.solution[j] = search.problem.AddPizzaToOrder(bestp, search.solution[j])
+	search.score += bestdelta
+}
+
+// Finds the heaviest ingredient that two pizzas of a random order share, and
+// swaps one of them with the best pizza lacking it, either from another random
+// order or among a few undelivered ones. With the weights favouring rare
+// ingredients, those end up spread across orders.
+func (search *localSearch) spreadRare() {
+	i := search.randomOrder()
+	pids := pizzasOf(search.solution[i])
+
+	shared := -1
+	for _, pid := range pids {
+		for _, ingredient := range search.problem.pizzas[pid].ingredientlist {
+			if search.solution[i].counts[ingredient] < 2 {
+				continue
+			}
+			if shared == -1 || search.problem.weight(ingredient) > search.problem.weight(shared) ||
+				(search.problem.weight(ingredient) == search.problem.weight(shared) && ingredient < shared) {
+				shared = ingredient
+			}
+		}
+	}
+
+	if shared == -1 {
+		return
+	}
+
+	var carriers []int
+	for _, pid := range pids {
+		if search.problem.pizzas[pid].ingredients.Has(shared) {
+			carriers = append(carriers, pid)
+		}
+	}
+	p := carriers[search.rng.Intn(len(carriers))]
+
+	j := -1
+	var candidates []int
+	if search.rng.Intn(2) == 0 || len(search.pool) == 0 {
+		if j = search.randomOrder(); j == i {
+			return
+		}
+		candidates = pizzasOf(search.solution[j])
+	} else {
+		for k := 0; k < 8; k++ {
+			candidates = append(candidates, search.pool[search.rng.Intn(len(search.pool))])
+		}
+	}
+
+	bestdelta, bestq := -1, -1
+	for _, q := range candidates {
+		if search.problem.pizzas[q].ingredients.Has(shared) {
+			continue
+		}
+
+		delta := search.problem.SwapDelta(search.solution[i], p, q)
+		if j != -1 {
+			delta += search.problem.SwapDelta(search.solution[j], q, p)
+		}
+		if delta > bestdelta {
+			bestdelta, bestq = delta, q
+		}
+	}
+
+	if bestdelta < 0 {
+		return
+	}
+
+	search.solution[i] = search.problem.RemovePizzaToOrder(p, search.solution[i])
+	search.solution[i] = search.problem.AddPizzaToOrder(bestq, search.solution[i])
+	if j != -1 {
+		search.solution[j] = search.problem.RemovePizzaToOrder(bestq, search.solution[j])
+		search.solution[j] = search.problem.AddPizzaToOrder(p, search.solution[j])
+	} else {
+		search.take(bestq)
+		search.release(p)
+	}
+	search.score += bestdelta
+}
+
+// Returns a random team size with idle teams, or 0 if all teams are served.
+func (search *localSearch) idleSize() int {
+	sizes := make([]int, 0, 3)
+	for size := 2; size <= 4; size++ {
+		if search.served[size] < search.problem.Teams(size) {
+			sizes = append(sizes, size)
+		}
+	}
+
+	if len(sizes) == 0 {
+		return 0
+	}
+
+	return sizes[search.rng.Intn(len(sizes))]
+}
+
+// Builds an order of `osize` pizzas greedily by marginal gain among the given
+// pizzas and a few random undelivered ones. Nothing is taken out of the pool.
+func (search *localSearch) buildOrder(osize int, pids []int) Order {
+	candidates := append([]int{}, pids...)
+	for k := 0; k < 4*osize && len(search.pool) > 0; k++ {
+		candidates = append(candidates, search.pool[search.rng.Intn(len(search.pool))])
+	}
+
+	order := NewOrder()
+	for len(order.pizzaids) < osize {
+		best, bestdelta := -1, -1
+		for _, pid := range candidates {
+			if order.pizzaids[pid] {
+				continue
+			}
+			if delta := search.problem.SwapDelta(order, -1, pid); delta > bestdelta {
+				best, bestdelta = pid, delta
+			}
+		}
+
+		if best == -1 {
+			break
+		}
+		order = search.problem.AddPizzaToOrder(best, order)
+	}
+
+	return order
+}
+
+// Serves an idle team with undelivered pizzas.
+func (search *localSearch) addOrder() {
+	size := search.idleSize()
+	if size == 0 || len(search.pool) < size {
+		return
+	}
+
+	order := search.buildOrder(size, nil)
+	if len(order.pizzaids) < size {
+		return
+	}
+
+	for pid := range order.pizzaids {
+		search.take(pid)
+	}
+	search.solution = append(search.solution, order)
+	search.served[size]++
+	search.score += order.score
+}
+
+// Drops a random order and serves a team of any available size in its place,
+// with its pizzas and undelivered ones. Dropping an order alone never pays
+// off, so this is the only way orders leave the solution.
+func (search *localSearch) replaceOrder() {
+	i := search.randomOrder()
+	old := search.solution[i]
+
+	search.served[len(old.pizzaids)]--
+	size := search.idleSize()
+
+	order := search.buildOrder(size, pizzasOf(old))
+	if len(order.pizzaids) < size || order.score < old.score {
+		search.served[len(old.pizzaids)]++
+		return
+	}
+
+	for pid := range old.pizzaids {
+		search.release(pid)
+	}
+	for pid := range order.pizzaids {
+		search.take(pid)
+	}
+	search.solution[i] = order
+	search.served[size]++
+	search.score += order.score - old.score
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImproveKeepsSubmissionValid(t *testing.T) {
+	problem := Parse("in/b.txt")
+	solution, score := problem.Import("out/b.txt")
+
+	isolution, iscore := problem.Improve(context.Background(), solution, 0.5, 1)
+	if iscore < score {
+		t.Errorf("score went down from %d to %d", score, iscore)
+	}
+
+	filename := filepath.Join(t.TempDir(), "b.txt")
+	problem.Export(isolution, filename)
+
+	validated, violations := problem.Validate(filename)
+	for _, violation := range violations {
+		t.Errorf("%v", violation)
+	}
+
+	if validated.Score() != iscore {
+		t.Errorf("got score %d, submission scores %d", iscore, validated.Score())
+	}
+}
+
+// Starting from an empty solution, the search must serve teams on its own.
+func TestImproveServesIdleTeams(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	solution, score := problem.Improve(context.Background(), Solution{}, 0.1, 1)
+	if len(solution) == 0 || score == 0 {
+		t.Errorf("got %d orders scoring %d, want some", len(solution), score)
+	}
+}
+
+// Whatever the weights, the search must keep the submission valid and its
+// score right.
+func TestImproveWithWeights(t *testing.T) {
+	problem := Parse("in/b.txt")
+	weights, err := problem.IngredientWeights(WeightingInverse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problem.weights = weights
+
+	solution, _ := problem.Solve(context.Background())
+	isolution, iscore := problem.Improve(context.Background(), solution, 0.5, 1)
+	if iscore != isolution.Score() {
+		t.Errorf("got score %d, solution scores %d", iscore, isolution.Score())
+	}
+}
+
+// Once cancelled, the search must return the solution found so far at once.
+func TestImproveCancelled(t *testing.T) {
+	problem := Parse("in/b.txt")
+	solution, score := problem.Import("out/b.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	isolution, iscore := problem.Improve(ctx, solution, 3600, 1)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v", elapsed)
+	}
+	if iscore != score || isolution.Score() != score {
+		t.Errorf("got score %d, solution scores %d, want %d", iscore, isolution.Score(), score)
+	}
+}
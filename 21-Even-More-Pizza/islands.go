@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Improves the solution for `maxtime` seconds, or until `ctx` is cancelled,
+// with `islands` searches running in parallel, each on its own copy of the
+// solution. Even islands run the local search of Improve and odd ones the large
+// neighbourhood search dealing `k` orders again, each drawing its moves from
+// its own seed.
+//
+// Every `migration` seconds the islands stop, and the best solution found so
+// far is copied to all of them before they resume: the islands explore apart
+// between migrations, and all build on the best of them afterwards.
+func (problem Problem) IslandSearch(ctx context.Context, solution Solution, islands int, migration float64, k int, maxtime float64, seed int64) (Solution, int) {
+	if islands < 1 {
+		islands = 1
+	}
+	if migration <= 0 {
+		migration = maxtime
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	best, bestscore := solution, solution.Score()
+
+	// Islands only see themselves, progress is published at migrations
+	island := problem
+	island.tracker = nil
+
+	start := time.Now()
+	for epoch := 1; ; epoch++ {
+		epochtime := min(migration, maxtime-time.Since(start).Seconds())
+		if epochtime <= 0 || ctx.Err() != nil {
+			break
+		}
+
+		seeds := make([]int64, islands)
+		for n := range seeds {
+			seeds[n] = rng.Int63()
+		}
+
+		solutions := make([]Solution, islands)
+		scores := make([]int, islands)
+
+		var wg sync.WaitGroup
+		for n := 0; n < islands; n++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				if n%2 == 0 {
+					solutions[n], scores[n] = island.Improve(ctx, best.Clone(), epochtime, seeds[n])
+				} else {
+					solutions[n], scores[n] = island.LargeNeighbourhoodSearch(ctx, best.Clone(), k, epochtime, seeds[n])
+				}
+			}(n)
+		}
+		wg.Wait()
+
+		for n, score := range scores {
+			if score > bestscore {
+				best, bestscore = solutions[n], score
+				fmt.Println("[*] Epoch", epoch, "- island", n, "found score", score)
+			}
+		}
+
+		problem.tracker.Move(bestscore, func() []byte { return problem.Format(best) })
+	}
+
+	return best, bestscore
+}
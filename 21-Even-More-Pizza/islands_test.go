@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIslandSearch(t *testing.T) {
+	problem := Parse("in/b.txt")
+	solution, score := problem.Import("out/b.txt")
+	original := make([][]int, len(solution))
+	for i, order := range solution {
+		original[i] = pizzasOf(order)
+	}
+
+	isolution, iscore := problem.IslandSearch(context.Background(), solution, 3, 0.2, 2, 0.5, 1)
+	if iscore < score {
+		t.Errorf("score went down from %d to %d", score, iscore)
+	}
+
+	for i, order := range solution {
+		if !reflect.DeepEqual(pizzasOf(order), original[i]) {
+			t.Fatalf("order %d went from %v to %v", i, original[i], pizzasOf(order))
+		}
+	}
+
+	filename := filepath.Join(t.TempDir(), "b.txt")
+	problem.Export(isolution, filename)
+
+	validated, violations := problem.Validate(filename)
+	for _, violation := range violations {
+		t.Errorf("%v", violation)
+	}
+
+	if validated.Score() != iscore {
+		t.Errorf("got score %d, submission scores %d", iscore, validated.Score())
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Improves the solution by large neighbourhood search for `maxtime` seconds, or
+// until `ctx` is cancelled: each step destroys `k` orders, pools their pizzas
+// and deals them again to the same teams in the best possible way. Since every
+// way is tried, k should stay small: with k = 3 orders of 4 pizzas there are
+// 5775 of them.
+//
+// Half of the time the destroyed orders are drawn at random, otherwise they are
+// the ones with the lowest score per pizza among a few random picks.
+func (problem Problem) LargeNeighbourhoodSearch(ctx context.Context, solution Solution, k int, maxtime float64, seed int64) (Solution, int) {
+	if k > len(solution) {
+		k = len(solution)
+	}
+
+	if k < 2 {
+		return solution, solution.Score()
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	score := solution.Score()
+	snapshot := func() []byte { return problem.Format(solution) }
+
+	start := time.Now()
+	iteration := 0
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		iteration++
+		if iteration%10000 == 0 {
+			fmt.Println("Iteration", iteration, "Score:", score)
+		}
+
+		var destroyed []int
+		if rng.Intn(2) == 0 {
+			destroyed = randomOrders(solution, k, rng)
+		} else {
+			destroyed = worstOrders(solution, k, rng)
+		}
+
+		before := 0
+		for _, i := range destroyed {
+			before += solution[i].score
+		}
+
+		orders, after := problem.Repair(solution, destroyed)
+		if after > before {
+			for n, i := range destroyed {
+				solution[i] = orders[n]
+			}
+			score += after - before
+			fmt.Printf("[*] Improvement (orders %v dealt again): %d\n", destroyed, score)
+		}
+
+		problem.tracker.Move(score, snapshot)
+	}
+
+	return solution, score
+}
+
+// Returns the indices of `k` distinct random orders.
+func randomOrders(solution Solution, k int, rng *rand.Rand) []int {
+	chosen := make(map[int]bool)
+	indices := make([]int, 0, k)
+	for len(indices) < k {
+		if i := rng.Intn(len(solution)); !chosen[i] {
+			chosen[i] = true
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// Returns the indices of `k` distinct orders, each the one with the lowest
+// score per pizza among a few random ones.
+func worstOrders(solution Solution, k int, rng *rand.Rand) []int {
+	const tournament = 8
+
+	perpizza := func(i int) float64 {
+		return float64(solution[i].score) / float64(len(solution[i].pizzaids))
+	}
+
+	chosen := make(map[int]bool)
+	indices := make([]int, 0, k)
+	for len(indices) < k {
+		worst := -1
+		for t := 0; t < tournament; t++ {
+			if i := rng.Intn(len(solution)); !chosen[i] && (worst == -1 || perpizza(i) < perpizza(worst)) {
+				worst = i
+			}
+		}
+
+		if worst != -1 {
+			chosen[worst] = true
+			indices = append(indices, worst)
+		}
+	}
+
+	return indices
+}
+
+// Finds by exhaustive search the best way to deal the pizzas of the given
+// orders to the same teams. Returns the new orders, in the same order as
+// `indices`, and their total score.
+func (problem Problem) Repair(solution Solution, indices []int) ([]Order, int) {
+	var pool []int
+	sizes := make([]int, len(indices))
+	for n, i := range indices {
+		pool = append(pool, pizzasOf(solution[i])...)
+		sizes[n] = len(solution[i].pizzaids)
+	}
+
+	// Identical pizzas are interchangeable: keep them next to each other and
+	// deal them in order, so that deals only swapping them can be skipped
+	sort.Slice(pool, func(a, b int) bool {
+		ca, cb := problem.class[pool[a]], problem.class[pool[b]]
+		if ca != cb {
+			return ca < cb
+		}
+		return pool[a] < pool[b]
+	})
+
+	// Teams of the same size are interchangeable: deal to them one after the
+	// other, so that symmetric deals can be skipped
+	teams := make([]int, len(indices))
+	for n := range teams {
+		teams[n] = n
+	}
+	sort.SliceStable(teams, func(a, b int) bool { return sizes[teams[a]] > sizes[teams[b]] })
+
+	r := &repair{
+		problem: problem,
+		pool:    pool,
+		used:    make([]bool, len(pool)),
+		sizes:   make([]int, len(teams)),
+		firsts:  make([]int, len(teams)),
+		orders:  make([]Order, len(teams)),
+		best:    -1,
+	}
+	for n, team := range teams {
+		r.sizes[n] = sizes[team]
+		r.orders[n] = NewOrder()
+	}
+
+	r.deal(0, 0, 0)
+
+	orders := make([]Order, len(indices))
+	for n, team := range teams {
+		orders[team] = r.bestorders[n]
+	}
+
+	return orders, r.best
+}
+
+// State of the exhaustive search of Repair.
+type repair struct {
+	problem    Problem
+	pool       []int   // IDs of the pizzas to deal
+	used       []bool  // Whether each pizza of the pool has been dealt
+	sizes      []int   // Size of each team, largest first
+	firsts     []int   // Index in the pool of the first pizza of each team
+	orders     []Order // Orders being dealt
+	best       int     // Best total score found so far
+	bestorders []Order // Orders achieving it
+}
+
+// Deals the next pizza of team `g`, choosing among the pool from index `from`
+// on, given the total score `score` of the teams already dealt.
+func (r *repair) deal(g, from, score int) {
+	if g == len(r.orders) {
+		if score > r.best {
+			r.best = score
+			r.bestorders = make([]Order, len(r.orders))
+			for n, order := range r.orders {
+				r.bestorders[n] = order.Clone()
+			}
+		}
+		return
+	}
+
+	order := r.orders[g]
+	if len(order.pizzaids) == r.sizes[g] {
+		r.deal(g+1, 0, score+order.score)
+		return
+	}
+
+	if len(order.pizzaids) == 0 && g > 0 && r.sizes[g] == r.sizes[g-1] {
+		from = r.firsts[g-1] + 1
+	}
+
+	for p := from; p < len(r.pool); p++ {
+		if r.used[p] {
+			continue
+		}
+		if p > 0 && !r.used[p-1] && r.problem.class[r.pool[p-1]] == r.problem.class[r.pool[p]] {
+			continue
+		}
+
+		if len(order.pizzaids) == 0 {
+			r.firsts[g] = p
+		}
+
+		r.used[p] = true
+		r.orders[g] = r.problem.AddPizzaToOrder(r.pool[p], order)
+		r.deal(g, p+1, score)
+		r.orders[g] = r.problem.RemovePizzaToOrder(r.pool[p], r.orders[g])
+		r.used[p] = false
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Returns the best total score of dealing the pizzas of the given orders to the
+// same teams, trying every assignment of pizzas to teams.
+func bruteForceRepair(problem Problem, solution Solution, indices []int) int {
+	var pool, sizes []int
+	for _, i := range indices {
+		pool = append(pool, pizzasOf(solution[i])...)
+		sizes = append(sizes, len(solution[i].pizzaids))
+	}
+
+	best := -1
+	assignment := make([]int, len(pool))
+	for {
+		orders := make([]Order, len(sizes))
+		for n := range orders {
+			orders[n] = NewOrder()
+		}
+		for p, n := range assignment {
+			orders[n] = problem.AddPizzaToOrder(pool[p], orders[n])
+		}
+
+		valid, score := true, 0
+		for n, order := range orders {
+			valid = valid && len(order.pizzaids) == sizes[n]
+			score += order.score
+		}
+		if valid && score > best {
+			best = score
+		}
+
+		// Next assignment, counting in base len(sizes)
+		p := 0
+		for p < len(assignment) && assignment[p] == len(sizes)-1 {
+			assignment[p] = 0
+			p++
+		}
+		if p == len(assignment) {
+			return best
+		}
+		assignment[p]++
+	}
+}
+
+func TestRepair(t *testing.T) {
+	problem := Parse("in/b.txt")
+	solution, _ := problem.Import("out/b.txt")
+	rng := rand.New(rand.NewSource(1))
+
+	for step := 0; step < 20; step++ {
+		indices := randomOrders(solution, 2+step%2, rng)
+
+		orders, score := problem.Repair(solution, indices)
+		if want := bruteForceRepair(problem, solution, indices); score != want {
+			t.Fatalf("orders %v: got score %d, want %d", indices, score, want)
+		}
+
+		dealt := make(map[int]bool)
+		total := 0
+		for n, i := range indices {
+			if len(orders[n].pizzaids) != len(solution[i].pizzaids) {
+				t.Fatalf("order %d: got %d pizzas, want %d", i, len(orders[n].pizzaids), len(solution[i].pizzaids))
+			}
+			for pid := range orders[n].pizzaids {
+				dealt[pid] = true
+			}
+			total += scoreFromScratch(problem, orders[n])
+		}
+
+		if total != score {
+			t.Errorf("orders %v: got score %d, orders score %d", indices, score, total)
+		}
+
+		for _, i := range indices {
+			for pid := range solution[i].pizzaids {
+				if !dealt[pid] {
+					t.Errorf("orders %v: pizza %d was not dealt", indices, pid)
+				}
+			}
+		}
+	}
+}
+
+// Skipping the deals that only swap identical pizzas must not miss the best.
+func TestRepairIdenticalPizzas(t *testing.T) {
+	problem := Parse("in/b.txt")
+
+	var twins [][]int
+	for _, pids := range problem.classes {
+		if len(pids) >= 2 && len(twins) < 7 {
+			twins = append(twins, pids[:2])
+		}
+	}
+
+	// Identical pizzas together in some orders, apart in others
+	solution := Solution{NewOrder(), NewOrder(), NewOrder(), NewOrder()}
+	deal := [][2]int{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {0, 1}, {2, 3}, {0, 1}}
+	for k, pair := range twins {
+		for n, pid := range pair {
+			solution[deal[k][n]] = problem.AddPizzaToOrder(pid, solution[deal[k][n]])
+		}
+	}
+
+	for _, indices := range [][]int{{0, 1}, {2, 3}, {0, 2}, {1, 3}} {
+		_, score := problem.Repair(solution, indices)
+		if want := bruteForceRepair(problem, solution, indices); score != want {
+			t.Errorf("orders %v: got score %d, want %d", indices, score, want)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mcieno/HashCode/internal/dashboard"
+	"github.com/mcieno/HashCode/internal/runner"
+)
+
+// The datasets solve and improve process.
+func datasets() []runner.Job {
+	return []runner.Job{
+		//{Dataset: "a", Title: "A - example", Input: "in/a.txt", Output: "out/a.txt", Start: "out/a.txt"},
+		{Dataset: "b", Title: "B - little bit of everything", Input: "in/b.txt", Output: "out/b.txt", Start: "out/b.txt"},
+		{Dataset: "c", Title: "C - many ingredients", Input: "in/c.txt", Output: "out/c.txt", Start: "out/c.txt"},
+		{Dataset: "d", Title: "D - many pizzas", Input: "in/d.txt", Output: "out/d.txt", Start: "out/d.txt"},
+		{Dataset: "e", Title: "E - many teams", Input: "in/e.txt", Output: "out/e.txt", Start: "out/e.txt"},
+	}
+}
+
+// Solves every dataset from scratch with `construct` and `pool`, publishing the
+// progress to `progress`.
+func solveMain(ctx context.Context, pool runner.Runner, jobs []runner.Job, construct func(context.Context, Problem) (Solution, int), progress *dashboard.Progress) {
+	results := pool.Run(ctx, jobs, func(ctx context.Context, job runner.Job) (int, error) {
+		fmt.Println("[+] Solving problem", job.Dataset)
+		problem := Parse(job.Input)
+		fmt.Println(
+			job.Dataset,
+			"[*] Problem parsed from file",
+			problem.M,
+			problem.T2,
+			problem.T3,
+			problem.T4,
+		)
+		fmt.Println(job.Dataset, "[*]", len(problem.classes), "distinct pizzas,", problem.Duplicates(), "duplicates")
+
+		problem.tracker = progress.Track(job.Dataset, 0, nil)
+		solution, score := construct(ctx, problem)
+		problem.tracker.Done(score, func() []byte { return problem.Format(solution) })
+
+		fname := fmt.Sprintf("%s%d", job.Output, score)
+		problem.Export(solution, fname)
+		fmt.Println(job.Dataset, "[*] Solution with score", score, "written to", fname)
+
+		return score, nil
+	})
+
+	fmt.Print(runner.Summary(results))
+}
+
+// Imports the current solution of every dataset and further improves it with
+// `optimise` for the time budget of the dataset, publishing the progress to
+// `progress`. Interrupted optimisations still export what they have found.
+func improveMain(ctx context.Context, pool runner.Runner, jobs []runner.Job, optimise func(context.Context, Problem, Solution, float64) (Solution, int), progress *dashboard.Progress) {
+	results := pool.Run(ctx, jobs, func(ctx context.Context, job runner.Job) (int, error) {
+		problem := Parse(job.Input)
+		fmt.Println(
+			job.Dataset,
+			"[*] Problem parsed from file:",
+			problem.M,
+			problem.T2,
+			problem.T3,
+			problem.T4,
+		)
+		fmt.Println(job.Dataset, "[*]", len(problem.classes), "distinct pizzas,", problem.Duplicates(), "duplicates")
+
+		fmt.Println(job.Dataset, "[*] Importing...")
+		solution, score := problem.Import(job.Start)
+		fmt.Println(
+			job.Dataset,
+			"[*] Solution imported - score:",
+			score,
+		)
+
+		problem.tracker = progress.Track(job.Dataset, score, func() []byte { return problem.Format(solution) })
+		isolution, iscore := optimise(ctx, problem, solution, runner.Seconds(ctx))
+		problem.tracker.Done(iscore, func() []byte { return problem.Format(isolution) })
+		fmt.Println(job.Dataset, "[*] Final solution has score", iscore)
+
+		fname := fmt.Sprintf("%s%d", job.Output, iscore)
+		problem.Export(isolution, fname)
+		fmt.Println(job.Dataset, "[*] Solution written to", fname)
+
+		return iscore, nil
+	})
+
+	fmt.Print(runner.Summary(results))
+}
+
+// Returns where optimisers publish their progress, serving it on `addr` unless
+// empty. The optimisations run anyway if the dashboard cannot be served.
+func newProgress(addr string) *dashboard.Progress {
+	progress := dashboard.NewProgress()
+	if addr != "" {
+		if err := dashboard.Serve(addr, "Even More Pizza", progress); err != nil {
+			fmt.Fprintln(os.Stderr, "[!] Dashboard unavailable:", err)
+		}
+	}
+
+	return progress
+}
+
+// Validates a submission and prints its score, broken down by team size.
+func scoreMain(args []string) {
+	cmd := flag.NewFlagSet("score", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem the submission is for")
+	cmd.Parse(args)
+
+	if *input == "" || cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . score -in <problem> <submission>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	problem := Parse(*input)
+	solution, violations := problem.Validate(cmd.Arg(0))
+
+	served, scores := solution.Breakdown()
+	for size := 2; size <= 4; size++ {
+		fmt.Printf(
+			"[*] %d-person teams: %d/%d served, score %d\n",
+			size,
+			served[size],
+			problem.Teams(size),
+			scores[size],
+		)
+	}
+	fmt.Println("[*] Total score:", solution.Score())
+
+	if len(violations) > 0 {
+		for _, violation := range violations {
+			fmt.Println("[!]", violation)
+		}
+		fmt.Println("[!] Invalid submission:", len(violations), "violations")
+		os.Exit(1)
+	}
+}
+
+// Prints the diagnostics of a submission, as text or CSV.
+func reportMain(args []string) {
+	cmd := flag.NewFlagSet("report", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem the submission is for")
+	format := cmd.String("format", "text", "output format: text or csv")
+	bins := cmd.Int("bins", 10, "ranges of the histogram of the scores of the orders")
+	cmd.Parse(args)
+
+	if *input == "" || cmd.NArg() != 1 || (*format != "text" && *format != "csv") {
+		fmt.Fprintln(os.Stderr, "usage: go run . report -in <problem> [-format text|csv] <submission>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	problem := Parse(*input)
+	solution, _ := problem.Import(cmd.Arg(0))
+	report := problem.Report(solution)
+
+	var err error
+	if *format == "csv" {
+		err = report.WriteCSV(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout, *bins)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Parses a list of pizza IDs and ranges of them, such as "0-9,12".
+func parseIDs(spec string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(spec, ",") {
+		var from, to int
+		if _, err := fmt.Sscanf(part, "%d-%d", &from, &to); err != nil {
+			if _, err := fmt.Sscanf(part, "%d", &from); err != nil {
+				return nil, fmt.Errorf("invalid pizza IDs %q: %w", part, err)
+			}
+			to = from
+		}
+		for id := from; id <= to; id++ {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// Writes the quadratic program of a dataset, or of a sub-problem of it.
+func qpMain(args []string) {
+	cmd := flag.NewFlagSet("qp", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem")
+	output := cmd.String("o", "", "file to write the model to")
+	pizzas := cmd.String("pizzas", "", "pizza IDs to use, e.g. 0-9,12 (default all)")
+	teams := cmd.String("teams", "", "number of 2-, 3- and 4-person teams to serve, e.g. 1,2,1 (default all)")
+	cmd.Parse(args)
+
+	if *input == "" || *output == "" || cmd.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: go run . qp -in <problem> -o <model> [-pizzas <ids>] [-teams <T2,T3,T4>]")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	problem := Parse(*input)
+
+	pids := make([]int, problem.M)
+	for pid := range pids {
+		pids[pid] = pid
+	}
+	if *pizzas != "" {
+		var err error
+		if pids, err = parseIDs(*pizzas); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for _, pid := range pids {
+			if pid < 0 || pid >= problem.M {
+				fmt.Fprintln(os.Stderr, "pizza", pid, "does not exist")
+				os.Exit(2)
+			}
+		}
+	}
+
+	counts := []int{problem.T2, problem.T3, problem.T4}
+	if *teams != "" {
+		if _, err := fmt.Sscanf(*teams, "%d,%d,%d", &counts[0], &counts[1], &counts[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid teams %q: %v\n", *teams, err)
+			os.Exit(2)
+		}
+	}
+
+	var sizes []int
+	for k, count := range counts {
+		for t := 0; t < count; t++ {
+			sizes = append(sizes, k+2)
+		}
+	}
+
+	problem.ExportQP(pids, sizes, *output)
+	fmt.Println("[*] Model of", len(pids), "pizzas and", len(sizes), "teams written to", *output)
+}
+
+// Turns the solution a solver found for a quadratic program into a submission.
+func qpsolMain(args []string) {
+	cmd := flag.NewFlagSet("qpsol", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem the model is for")
+	output := cmd.String("o", "", "prefix of the submission file, completed by its score")
+	cmd.Parse(args)
+
+	if *input == "" || *output == "" || cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . qpsol -in <problem> -o <prefix> <solver solution>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	problem := Parse(*input)
+	solution := problem.ImportQPSolution(cmd.Arg(0))
+	score := solution.Score()
+
+	problem.Export(solution, fmt.Sprintf("%s%d", *output, score))
+	fmt.Println("[*] Solution of", len(solution), "orders with score", score, "written to", fmt.Sprintf("%s%d", *output, score))
+}
+
+// Prints upper bounds of the score of every dataset, next to the score of the
+// solution in out/ and how far it is from them.
+func boundMain(args []string) {
+	cmd := flag.NewFlagSet("bound", flag.ExitOnError)
+	cmd.Parse(args)
+
+	fmt.Println("| DATA SET |          SCORE |    UPPER BOUND |     GAP |")
+	fmt.Println("| -------- | -------------: | -------------: | ------: |")
+
+	total, totalbound := 0, 0
+	for _, letter := range []string{"a", "b", "c", "d", "e"} {
+		problem := Parse(fmt.Sprintf("in/%s.txt", letter))
+		bound := problem.UpperBound().Value()
+
+		score := 0
+		if _, err := os.Stat(fmt.Sprintf("out/%s.txt", letter)); err == nil {
+			_, score = problem.Import(fmt.Sprintf("out/%s.txt", letter))
+		}
+
+		fmt.Printf("| %-8s | %14d | %14d | %6.2f%% |\n", letter, score, bound, gap(score, bound))
+		total += score
+		totalbound += bound
+	}
+
+	fmt.Printf("| %-8s | %14d | %14d | %6.2f%% |\n", "total", total, totalbound, gap(total, totalbound))
+}
+
+// Returns how far, in percent of the bound, a score is from it.
+func gap(score, bound int) float64 {
+	if bound == 0 {
+		return 0
+	}
+
+	return 100 * float64(bound-score) / float64(bound)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: go run . <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  solve    solve every dataset from scratch")
+	fmt.Fprintln(os.Stderr, "  improve  further improve the solutions in out/")
+	fmt.Fprintln(os.Stderr, "  lns      further improve the solutions in out/ by dealing orders again")
+	fmt.Fprintln(os.Stderr, "  islands  further improve the solutions in out/ with parallel searches")
+	fmt.Fprintln(os.Stderr, "  score    validate and score a submission")
+	fmt.Fprintln(os.Stderr, "  report   print the diagnostics of the orders of a submission")
+	fmt.Fprintln(os.Stderr, "  bound    print upper bounds of the scores of the datasets")
+	fmt.Fprintln(os.Stderr, "  qp       write the quadratic program of a dataset")
+	fmt.Fprintln(os.Stderr, "  qpsol    turn a solver solution of the quadratic program into a submission")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	maxtime := cmd.Float64("maxtime", 3600, "seconds spent improving each dataset")
+	budgets := cmd.String("budgets", "", "seconds spent on specific datasets, e.g. c=600,e=120")
+	workers := cmd.Int("workers", runtime.NumCPU(), "datasets processed at once")
+	seed := cmd.Int64("seed", 1, "seed of the random number generator")
+	k := cmd.Int("k", 3, "orders dealt again by each step of lns and islands")
+	method := cmd.String("method", "greedy", "how solve builds solutions: greedy or matching")
+	partners := cmd.Int("partners", 8, "partners of each pizza considered by the matching")
+	islands := cmd.Int("islands", 4, "searches run in parallel on each dataset by islands")
+	migration := cmd.Float64("migration", 30, "seconds between migrations of the best solution to all islands")
+	addr := cmd.String("http", "", "serve a live progress dashboard on this address (e.g. :8080)")
+	weighting := cmd.String("weights", WeightingUniform, "how heuristics weigh ingredients: uniform, inverse or log")
+
+	// Stops at the first interrupt, still exporting what has been found, and
+	// dies at the second one
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Returns the runner and the datasets configured by the flags above
+	run := func() (runner.Runner, []runner.Job) {
+		cmd.Parse(os.Args[2:])
+
+		perdataset, err := runner.ParseBudgets(*budgets)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		switch *weighting {
+		case WeightingUniform, WeightingInverse, WeightingLog:
+		default:
+			fmt.Fprintln(os.Stderr, "unknown weighting scheme", *weighting)
+			os.Exit(2)
+		}
+
+		jobs := datasets()
+		for k := range jobs {
+			jobs[k].Budget = perdataset[jobs[k].Dataset]
+		}
+
+		return runner.Runner{Workers: *workers, Budget: time.Duration(*maxtime * float64(time.Second))}, jobs
+	}
+
+	// Returns the problem with its ingredients weighted as the flags say
+	weigh := func(problem Problem) Problem {
+		weights, err := problem.IngredientWeights(*weighting)
+		if err != nil {
+			panic(err)
+		}
+		problem.weights = weights
+		return problem
+	}
+
+	switch os.Args[1] {
+	case "score":
+		scoreMain(os.Args[2:])
+	case "report":
+		reportMain(os.Args[2:])
+	case "bound":
+		boundMain(os.Args[2:])
+	case "qp":
+		qpMain(os.Args[2:])
+	case "qpsol":
+		qpsolMain(os.Args[2:])
+	case "solve":
+		pool, jobs := run()
+		construct := func(ctx context.Context, problem Problem) (Solution, int) { return weigh(problem).Solve(ctx) }
+		switch *method {
+		case "greedy":
+		case "matching":
+			construct = func(ctx context.Context, problem Problem) (Solution, int) {
+				return weigh(problem).SolveMatching(ctx, *partners)
+			}
+		default:
+			fmt.Fprintln(os.Stderr, "unknown method", *method)
+			os.Exit(2)
+		}
+		solveMain(ctx, pool, jobs, construct, newProgress(*addr))
+	case "improve":
+		pool, jobs := run()
+		improveMain(ctx, pool, jobs, func(ctx context.Context, problem Problem, solution Solution, maxtime float64) (Solution, int) {
+			return weigh(problem).Improve(ctx, solution, maxtime, *seed)
+		}, newProgress(*addr))
+	case "lns":
+		pool, jobs := run()
+		improveMain(ctx, pool, jobs, func(ctx context.Context, problem Problem, solution Solution, maxtime float64) (Solution, int) {
+			return weigh(problem).LargeNeighbourhoodSearch(ctx, solution, *k, maxtime, *seed)
+		}, newProgress(*addr))
+	case "islands":
+		pool, jobs := run()
+		improveMain(ctx, pool, jobs, func(ctx context.Context, problem Problem, solution Solution, maxtime float64) (Solution, int) {
+			return weigh(problem).IslandSearch(ctx, solution, *islands, *migration, *k, maxtime, *seed)
+		}, newProgress(*addr))
+	default:
+		usage()
+	}
+}
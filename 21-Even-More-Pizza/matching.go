@@ -0,0 +1,196 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+)
+
+// An edge of the candidate graph: two pizzas that could be delivered together.
+type Pairing struct {
+	a, b   int // IDs of the pizzas, a < b
+	weight int // Score of an order of the two pizzas
+}
+
+// Returns the score of an order of pizzas `a` and `b`.
+func (problem Problem) pairWeight(a, b int) int {
+	union := problem.pizzas[a].ingredients.UnionLen(problem.pizzas[b].ingredients)
+	return union * union
+}
+
+// Returns the candidate graph of the matching: for each pizza, the `k` pizzas
+// it makes the most ingredients with.
+//
+// Identical pizzas have the same partners, so these are searched once per
+// class: the classes sharing ingredients with it are found through the
+// ingredient postings, which count how many each shares, and an order of
+// classes a and b has |a| + |b| minus that many ingredients. The classes
+// sharing none make |a| + |b|, so only the largest of them matter.
+//
+// Any partner makes at least as many ingredients as it has, so the k+1 largest
+// classes make k partners as good as the (k+1)-th of them: postings are sorted
+// by decreasing size and left as soon as their classes are too small to beat
+// that.
+func (problem Problem) CandidatePairs(k int) []Pairing {
+	size := make([]int, len(problem.classes))
+	bysize := make([]int, len(problem.classes))
+	for c, pids := range problem.classes {
+		size[c] = len(problem.pizzas[pids[0]].ingredientlist)
+		bysize[c] = c
+	}
+	sort.SliceStable(bysize, func(i, j int) bool { return size[bysize[i]] > size[bysize[j]] })
+
+	postings := make([][]int, len(problem.ingredients))
+	for _, c := range bysize {
+		for _, i := range problem.pizzas[problem.classes[c][0]].ingredientlist {
+			postings[i] = append(postings[i], c)
+		}
+	}
+
+	floor := 0 // Ingredients the k best partners of any class make at least
+	if k < len(bysize) {
+		floor = size[bysize[k]]
+	}
+
+	shared := make([]int, len(problem.classes)) // Ingredients each class shares with a
+	var touched []int                           // Classes sharing some
+	seen := make(map[[2]int]bool)
+	var pairs []Pairing
+
+	for a, pids := range problem.classes {
+		// Min-heap of the best partner classes so far, by union: as each has a
+		// pizza other than those of a, k of them make k partners
+		best := &candidateQueue{}
+		offer := func(b, union int) {
+			if best.Len() < k {
+				heap.Push(best, candidate{pid: b, gain: -union})
+			} else if union > -(*best)[0].gain {
+				(*best)[0] = candidate{pid: b, gain: -union}
+				heap.Fix(best, 0)
+			}
+		}
+
+		touched = touched[:0]
+		for _, i := range problem.pizzas[pids[0]].ingredientlist {
+			for _, b := range postings[i] {
+				if size[a]+size[b] <= floor {
+					break
+				}
+				if shared[b] == 0 {
+					touched = append(touched, b)
+				}
+				shared[b]++
+			}
+		}
+		for _, b := range touched {
+			if b != a || len(pids) > 1 {
+				offer(b, size[a]+size[b]-shared[b])
+			}
+		}
+		for _, b := range bysize {
+			if best.Len() == k && size[a]+size[b] <= -(*best)[0].gain {
+				break
+			}
+			if shared[b] == 0 && b != a {
+				offer(b, size[a]+size[b])
+			}
+		}
+		for _, b := range touched {
+			shared[b] = 0
+		}
+
+		partners := append(candidateQueue{}, *best...)
+		sort.Sort(sort.Reverse(partners))
+		for _, pid := range pids {
+			n := 0
+			for _, partner := range partners {
+				for _, other := range problem.classes[partner.pid] {
+					if n == k {
+						break
+					}
+					if other == pid {
+						continue
+					}
+
+					key := [2]int{min(pid, other), max(pid, other)}
+					if !seen[key] {
+						seen[key] = true
+						pairs = append(pairs, Pairing{a: key[0], b: key[1], weight: partner.gain * partner.gain})
+					}
+					n++
+				}
+			}
+		}
+	}
+
+	return pairs
+}
+
+// Builds a solution serving the teams decided by Plan from a maximum-weight
+// matching of the candidate graph: the heaviest pairs make the orders, team
+// sizes taking turns as in Solve, and 3- and 4-person orders are completed
+// greedily by marginal gain with the pizzas left out. `k` is the number of
+// partners of each pizza in the candidate graph. If `ctx` is cancelled the
+// matching stops where it is and the teams left are not served.
+func (problem Problem) SolveMatching(ctx context.Context, k int) (Solution, int) {
+	plan := problem.Plan()
+	fmt.Println("[*] Plan:", plan)
+
+	pairs := problem.CandidatePairs(k)
+	fmt.Println("[*] Candidate graph of", len(pairs), "pairs")
+
+	edges := make([]weightedEdge, len(pairs))
+	for e, pair := range pairs {
+		edges[e] = weightedEdge{i: pair.a, j: pair.b, w: pair.weight}
+	}
+	mate := maxWeightMatching(ctx, problem.M, edges)
+
+	var matched []Pairing
+	for a, b := range mate {
+		if a < b {
+			matched = append(matched, Pairing{a: a, b: b, weight: problem.pairWeight(a, b)})
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].weight > matched[j].weight })
+
+	// Team sizes in turns, as many as the plan serves
+	left := map[int]int{2: plan.teams[2], 3: plan.teams[3], 4: plan.teams[4]}
+	var sizes []int
+	for len(sizes) < plan.teams[2]+plan.teams[3]+plan.teams[4] {
+		for size := 2; size <= 4; size++ {
+			if left[size] > 0 {
+				sizes = append(sizes, size)
+				left[size]--
+			}
+		}
+	}
+
+	// Pairs making an order; the pizzas of the others are left to the builder
+	seeds := min(len(sizes), len(matched))
+	rpizzaids := make(map[int]bool)
+	for pid := 0; pid < problem.M; pid++ {
+		rpizzaids[pid] = true
+	}
+	for _, pair := range matched[:seeds] {
+		delete(rpizzaids, pair.a)
+		delete(rpizzaids, pair.b)
+	}
+	builder := problem.NewGreedyBuilder(rpizzaids)
+
+	solution := make(Solution, 0, len(sizes))
+	for n, size := range sizes {
+		if ctx.Err() != nil {
+			break
+		}
+
+		order := NewOrder()
+		if n < seeds {
+			order = problem.AddPizzaToOrder(matched[n].a, order)
+			order = problem.AddPizzaToOrder(matched[n].b, order)
+		}
+		solution = append(solution, builder.Extend(order, size))
+	}
+
+	return solution, solution.Score()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// Each pizza must be paired with partners as good as its k best ones, found by
+// trying them all.
+func TestCandidatePairs(t *testing.T) {
+	problem := Parse("in/b.txt")
+	const k = 4
+
+	found := make(map[int][]int)
+	for _, pair := range problem.CandidatePairs(k) {
+		if want := problem.pairWeight(pair.a, pair.b); pair.weight != want {
+			t.Fatalf("pair %v: got weight %d, want %d", pair, pair.weight, want)
+		}
+		found[pair.a] = append(found[pair.a], pair.weight)
+		found[pair.b] = append(found[pair.b], pair.weight)
+	}
+
+	for a := range problem.pizzas {
+		var weights []int
+		for b := range problem.pizzas {
+			if a != b {
+				weights = append(weights, problem.pairWeight(a, b))
+			}
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(weights)))
+
+		got := found[a]
+		sort.Sort(sort.Reverse(sort.IntSlice(got)))
+		for n := 0; n < k; n++ {
+			if got[n] != weights[n] {
+				t.Fatalf("pizza %d: got partners weighing %v, want the best %v", a, got[:k], weights[:k])
+			}
+		}
+	}
+}
+
+// The matching of the candidate graph must be at least as heavy as the greedy
+// one, heaviest pairs first.
+func TestMatchingCandidates(t *testing.T) {
+	problem := Parse("in/b.txt")
+	pairs := problem.CandidatePairs(8)
+
+	edges := make([]weightedEdge, len(pairs))
+	for e, pair := range pairs {
+		edges[e] = weightedEdge{i: pair.a, j: pair.b, w: pair.weight}
+	}
+	mate := maxWeightMatching(context.Background(), problem.M, edges)
+
+	total := 0
+	for a, b := range mate {
+		if b == -1 {
+			continue
+		}
+		if mate[b] != a {
+			t.Fatalf("pizza %d is matched with %d, which is matched with %d", a, b, mate[b])
+		}
+		if a < b {
+			total += problem.pairWeight(a, b)
+		}
+	}
+
+	greedy := make(map[int]bool)
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].weight > pairs[j].weight })
+	want := 0
+	for _, pair := range pairs {
+		if !greedy[pair.a] && !greedy[pair.b] {
+			greedy[pair.a], greedy[pair.b] = true, true
+			want += pair.weight
+		}
+	}
+
+	if total < want {
+		t.Errorf("got matching weight %d, greedy matching weighs %d", total, want)
+	}
+}
+
+func TestSolveMatching(t *testing.T) {
+	for _, dataset := range []string{"a", "b"} {
+		t.Run(dataset, func(t *testing.T) {
+			problem := Parse("in/" + dataset + ".txt")
+			solution, score := problem.SolveMatching(context.Background(), 8)
+
+			filename := filepath.Join(t.TempDir(), dataset+".txt")
+			problem.Export(solution, filename)
+
+			validated, violations := problem.Validate(filename)
+			for _, violation := range violations {
+				t.Errorf("%v", violation)
+			}
+
+			if validated.Score() != score {
+				t.Errorf("got score %d, submission scores %d", score, validated.Score())
+			}
+		})
+	}
+}
+
+// Once cancelled, no team is served.
+func TestSolveMatchingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if solution, score := Parse("in/b.txt").SolveMatching(ctx, 8); len(solution) != 0 || score != 0 {
+		t.Errorf("got %d orders scoring %d, want none", len(solution), score)
+	}
+}
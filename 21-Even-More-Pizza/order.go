@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Returns an empty order.
+func NewOrder() Order {
+	return Order{
+		score:    0,
+		pizzaids: make(map[int]bool),
+		counts:   make(map[int]int),
+	}
+}
+
+// Returns the size of the team the order is for: the declared one for orders
+// read by Validate, otherwise the number of pizzas.
+func (order Order) Team() int {
+	if order.team != 0 {
+		return order.team
+	}
+
+	return len(order.pizzaids)
+}
+
+// Removes a pizza from the order, updating its score in time proportional to
+// the number of ingredients of the pizza.
+func (problem Problem) RemovePizzaToOrder(pid int, order Order) Order {
+	if _, found := order.pizzaids[pid]; !found {
+		panic(fmt.Sprintf("Order %v does not have pizza %d", order, pid))
+	}
+
+	delete(order.pizzaids, pid)
+
+	for _, i := range problem.pizzas[pid].ingredientlist {
+		if order.counts[i]--; order.counts[i] == 0 {
+			delete(order.counts, i)
+		}
+	}
+
+	order.score = len(order.counts) * len(order.counts)
+
+	return order
+}
+
+// Adds a pizza to the order, updating its score in time proportional to the
+// number of ingredients of the pizza.
+func (problem Problem) AddPizzaToOrder(pid int, order Order) Order {
+	if _, found := order.pizzaids[pid]; found {
+		panic(fmt.Sprintf("Order %v already has pizza %d", order, pid))
+	}
+
+	order.pizzaids[pid] = true
+
+	for _, i := range problem.pizzas[pid].ingredientlist {
+		order.counts[i]++
+	}
+
+	order.score = len(order.counts) * len(order.counts)
+
+	return order
+}
+
+// Returns how much the score of the order would change by removing pizza `out`
+// and adding pizza `in`, without modifying it. Either can be -1 to only add or
+// only remove a pizza.
+func (problem Problem) SwapDelta(order Order, out, in int) int {
+	n := len(order.counts)
+
+	if out != -1 {
+		for _, i := range problem.pizzas[out].ingredientlist {
+			if order.counts[i] == 1 {
+				n--
+			}
+		}
+	}
+
+	if in != -1 {
+		for _, i := range problem.pizzas[in].ingredientlist {
+			if order.counts[i] == 0 || (order.counts[i] == 1 && out != -1 && problem.pizzas[out].ingredients.Has(i)) {
+				n++
+			}
+		}
+	}
+
+	return n*n - order.score
+}
+
+// Builds an order with random remaining pizzas.
+func (problem Problem) RandomOrder(rpizzaids map[int]bool, osize int) (Order, map[int]bool) {
+	if osize < 1 {
+		panic("An order must contain pizzas")
+	}
+
+	if osize > len(rpizzaids) {
+		panic("Not enough pizzas")
+	}
+
+	order := NewOrder()
+
+	for i := 0; i < osize; i++ {
+		// Select a random pizza and add it to the order
+		for rpid := range rpizzaids {
+			order = problem.AddPizzaToOrder(rpid, order)
+			delete(rpizzaids, rpid)
+			break
+		}
+	}
+
+	return order, rpizzaids
+}
+
+// Deep copies an order
+func (order Order) Clone() Order {
+	clone := Order{score: order.score, pizzaids: make(map[int]bool), counts: make(map[int]int), team: order.team}
+	for k, v := range order.pizzaids {
+		clone.pizzaids[k] = v
+	}
+	for k, v := range order.counts {
+		clone.counts[k] = v
+	}
+
+	return clone
+}
+
+// Finds the best possible order of size "osize" by bruteforcing the first 2
+// pizzas and then greedily searching the remaining "osize - 2" ones.
+func (problem Problem) BestOrder2(rpizzaids map[int]bool, osize int) (Order, map[int]bool) {
+	if osize < 2 {
+		panic("An order must contain pizzas 2+ pizzas")
+	}
+
+	if osize > len(rpizzaids) {
+		panic("Not enough pizzas")
+	}
+
+	order := NewOrder()
+
+	// Add 2 random pizzas to the order
+	for rpid := range rpizzaids {
+		order = problem.AddPizzaToOrder(rpid, order)
+		delete(rpizzaids, rpid)
+		break
+	}
+
+	for rpid := range rpizzaids {
+		if exists, found := order.pizzaids[rpid]; !found || !exists {
+			order = problem.AddPizzaToOrder(rpid, order)
+			delete(rpizzaids, rpid)
+			break
+		}
+	}
+
+	for rpid := range rpizzaids {
+		for rrpid := range rpizzaids {
+			if rpid < rrpid {
+				combined := problem.pizzas[rpid].ingredients.UnionLen(
+					problem.pizzas[rrpid].ingredients,
+				)
+				newscore := combined * combined
+				if newscore > order.score {
+					order = problem.AddPizzaToOrder(rrpid, problem.AddPizzaToOrder(rpid, NewOrder()))
+				}
+			}
+		}
+	}
+
+	for pid := range order.pizzaids {
+		delete(rpizzaids, pid)
+	}
+
+	for len(order.pizzaids) < osize {
+		// Add the pizza that increases the order score the most
+		var bestrpid int
+		for rpid := range rpizzaids {
+			bestrpid = rpid
+			break
+		}
+
+		bestorder := problem.AddPizzaToOrder(bestrpid, order.Clone())
+		bestscore := bestorder.score
+
+		for rpid := range rpizzaids {
+			tentativeorder := problem.AddPizzaToOrder(rpid, order.Clone())
+			if tentativeorder.score > bestscore {
+				bestrpid = rpid
+				bestscore = tentativeorder.score
+			}
+		}
+
+		order = problem.AddPizzaToOrder(bestrpid, order)
+		delete(rpizzaids, bestrpid)
+	}
+
+	return order, rpizzaids
+}
+
+// Builds an order with the "best" remaining pizza. That is, the pizzas with the most ingredients.
+func (problem Problem) MostIngredientsOrder(rpizzaids map[int]bool, osize int) (Order, map[int]bool) {
+	if osize < 1 {
+		panic("An order must contain pizzas")
+	}
+
+	if osize > len(rpizzaids) {
+		panic("Not enough pizzas")
+	}
+
+	order := NewOrder()
+
+	for i := 0; i < osize; i++ {
+		bestpid := -1
+		for rpid := range rpizzaids {
+			if bestpid == -1 || len(problem.pizzas[rpid].ingredientlist) > len(problem.pizzas[bestpid].ingredientlist) {
+				bestpid = rpid
+			}
+		}
+		order = problem.AddPizzaToOrder(bestpid, order)
+		delete(rpizzaids, bestpid)
+	}
+
+	return order, rpizzaids
+}
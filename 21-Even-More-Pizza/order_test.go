@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/mcieno/HashCode/internal/ingredients"
+)
+
+// Returns the score of an order computed from scratch.
+func scoreFromScratch(problem Problem, order Order) int {
+	set := ingredients.Set(nil)
+	for pid := range order.pizzaids {
+		set = set.Union(problem.pizzas[pid].ingredients)
+	}
+
+	return set.Len() * set.Len()
+}
+
+func TestOrderIncrementalScore(t *testing.T) {
+	problem := Parse("in/b.txt")
+	rng := rand.New(rand.NewSource(1))
+
+	order := NewOrder()
+	for step := 0; step < 1000; step++ {
+		pid := rng.Intn(problem.M)
+		if order.pizzaids[pid] {
+			order = problem.RemovePizzaToOrder(pid, order)
+		} else {
+			order = problem.AddPizzaToOrder(pid, order)
+		}
+
+		if want := scoreFromScratch(problem, order); order.score != want {
+			t.Fatalf("step %d: got score %d, want %d", step, order.score, want)
+		}
+	}
+
+	clone := order.Clone()
+	for pid := range order.pizzaids {
+		order = problem.RemovePizzaToOrder(pid, order)
+	}
+
+	if order.score != 0 || len(order.counts) != 0 {
+		t.Errorf("got score %d and %d ingredients for an empty order", order.score, len(order.counts))
+	}
+
+	if want := scoreFromScratch(problem, clone); clone.score != want || clone.score == 0 {
+		t.Errorf("clone was modified along with the original order")
+	}
+}
+
+func TestImportDatasetA(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	solution, score := problem.Import("out/a.txt")
+	if score != 74 {
+		t.Errorf("got score %d, want %d", score, 74)
+	}
+
+	for i, order := range solution {
+		if want := scoreFromScratch(problem, order); order.score != want {
+			t.Errorf("order %d: got score %d, want %d", i, order.score, want)
+		}
+	}
+}
+
+func TestSwapDelta(t *testing.T) {
+	problem := Parse("in/b.txt")
+	rng := rand.New(rand.NewSource(1))
+
+	for step := 0; step < 1000; step++ {
+		order := NewOrder()
+		for len(order.pizzaids) < 1+rng.Intn(4) {
+			if pid := rng.Intn(problem.M); !order.pizzaids[pid] {
+				order = problem.AddPizzaToOrder(pid, order)
+			}
+		}
+
+		out, in := -1, -1
+		if rng.Intn(3) > 0 {
+			out = pizzasOf(order)[rng.Intn(len(order.pizzaids))]
+		}
+		for in == -1 && (out == -1 || rng.Intn(3) > 0) {
+			if pid := rng.Intn(problem.M); !order.pizzaids[pid] {
+				in = pid
+			}
+		}
+
+		delta := problem.SwapDelta(order, out, in)
+
+		before := order.score
+		if out != -1 {
+			order = problem.RemovePizzaToOrder(out, order)
+		}
+		if in != -1 {
+			order = problem.AddPizzaToOrder(in, order)
+		}
+
+		if want := order.score - before; delta != want {
+			t.Fatalf("step %d: swapping %d for %d: got delta %d, want %d", step, out, in, delta, want)
+		}
+	}
+}
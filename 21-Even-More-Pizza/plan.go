@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+)
+
+// A Plan tells how many teams of each size to serve.
+type Plan struct {
+	teams    map[int]int // Number of teams to serve by team size
+	expected float64     // Estimated score of the plan
+}
+
+// Returns the expected number of distinct ingredients in an order of `k`
+// pizzas drawn at random. An ingredient appearing on a fraction f of the
+// pizzas is missing from such an order with probability (1-f)^k.
+func (problem Problem) ExpectedIngredients(k int) float64 {
+	frequency := make([]int, len(problem.ingredients))
+	for _, pizza := range problem.pizzas {
+		for _, i := range pizza.ingredientlist {
+			frequency[i]++
+		}
+	}
+
+	expected := 0.0
+	for _, count := range frequency {
+		missing := 1.0
+		for j := 0; j < k; j++ {
+			missing *= 1 - float64(count)/float64(problem.M)
+		}
+		expected += 1 - missing
+	}
+
+	return expected
+}
+
+// Decides how many teams of each size to serve, so that at most M pizzas are
+// delivered and the expected score is maximum. An order of k pizzas is
+// expected to score ExpectedIngredients(k)², so the plan trades, e.g., one
+// 4-person team for two 2-person teams only when that pays off.
+func (problem Problem) Plan() Plan {
+	value := make(map[int]float64)
+	for size := 2; size <= 4; size++ {
+		u := problem.ExpectedIngredients(size)
+		value[size] = u * u
+	}
+
+	return problem.bestTeams(value)
+}
+
+// Returns how many teams of each size to serve, so that at most M pizzas are
+// delivered and the total value is maximum, given the value of serving a team
+// of each size.
+func (problem Problem) bestTeams(value map[int]float64) Plan {
+	best := Plan{teams: map[int]int{2: 0, 3: 0, 4: 0}, expected: -1}
+
+	for n4 := 0; n4 <= problem.T4 && 4*n4 <= problem.M; n4++ {
+		left := problem.M - 4*n4
+
+		// Once n4 is fixed, the total value is linear in n3 over the values
+		// of the same parity on either side of where all 2-person teams can
+		// no longer be served, so it peaks at the ends of those ranges
+		top := min(problem.T3, left/3)
+		candidates := []int{0, 1, top - 1, top}
+		if tight := (left - 2*problem.T2) / 3; tight > 0 {
+			candidates = append(candidates, tight-1, tight, tight+1, tight+2)
+		}
+
+		for _, n3 := range candidates {
+			if n3 < 0 || n3 > problem.T3 || 3*n3 > left {
+				continue
+			}
+
+			n2 := min(problem.T2, (left-3*n3)/2)
+			expected := float64(n2)*value[2] + float64(n3)*value[3] + float64(n4)*value[4]
+			if expected > best.expected {
+				best = Plan{teams: map[int]int{2: n2, 3: n3, 4: n4}, expected: expected}
+			}
+		}
+	}
+
+	return best
+}
+
+// Returns the number of pizzas the plan delivers.
+func (plan Plan) Pizzas() int {
+	return 2*plan.teams[2] + 3*plan.teams[3] + 4*plan.teams[4]
+}
+
+func (plan Plan) String() string {
+	return fmt.Sprintf(
+		"%d 2-person, %d 3-person, %d 4-person teams (%d pizzas, expected score %.0f)",
+		plan.teams[2],
+		plan.teams[3],
+		plan.teams[4],
+		plan.Pizzas(),
+		plan.expected,
+	)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestExpectedIngredients(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	// A single random pizza has, on average, (3 + 3 + 3 + 3 + 2) / 5 ingredients
+	if got := problem.ExpectedIngredients(1); math.Abs(got-2.8) > 1e-9 {
+		t.Errorf("got %f expected ingredients, want %f", got, 2.8)
+	}
+
+	for k := 1; k < 4; k++ {
+		if problem.ExpectedIngredients(k+1) <= problem.ExpectedIngredients(k) {
+			t.Errorf("expected ingredients do not grow from %d to %d pizzas", k, k+1)
+		}
+	}
+
+	if got := problem.ExpectedIngredients(100); got > float64(len(problem.ingredients)) {
+		t.Errorf("got %f expected ingredients, but there are only %d", got, len(problem.ingredients))
+	}
+}
+
+func TestPlan(t *testing.T) {
+	for _, dataset := range []string{"a", "b", "c", "d", "e"} {
+		t.Run(dataset, func(t *testing.T) {
+			problem := Parse("in/" + dataset + ".txt")
+			plan := problem.Plan()
+
+			if plan.Pizzas() > problem.M {
+				t.Errorf("plan delivers %d pizzas, only %d exist", plan.Pizzas(), problem.M)
+			}
+
+			for size := 2; size <= 4; size++ {
+				if plan.teams[size] < 0 || plan.teams[size] > problem.Teams(size) {
+					t.Errorf("plan serves %d teams of %d people, %d exist", plan.teams[size], size, problem.Teams(size))
+				}
+			}
+
+			// With enough pizzas for everybody, every team is served
+			if 2*problem.T2+3*problem.T3+4*problem.T4 <= problem.M {
+				for size := 2; size <= 4; size++ {
+					if plan.teams[size] != problem.Teams(size) {
+						t.Errorf("plan serves %d teams of %d people, %d could be", plan.teams[size], size, problem.Teams(size))
+					}
+				}
+			}
+		})
+	}
+}
+
+// bestTeams must find the best split of teams, as trying all of them does.
+func TestBestTeams(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for step := 0; step < 500; step++ {
+		problem := Problem{M: rng.Intn(40), T2: rng.Intn(10), T3: rng.Intn(10), T4: rng.Intn(10)}
+		value := map[int]float64{2: float64(rng.Intn(20)), 3: float64(rng.Intn(30)), 4: float64(rng.Intn(40))}
+
+		want := 0.0
+		for n2 := 0; n2 <= problem.T2; n2++ {
+			for n3 := 0; n3 <= problem.T3; n3++ {
+				for n4 := 0; n4 <= problem.T4; n4++ {
+					if 2*n2+3*n3+4*n4 <= problem.M {
+						want = math.Max(want, float64(n2)*value[2]+float64(n3)*value[3]+float64(n4)*value[4])
+					}
+				}
+			}
+		}
+
+		if got := problem.bestTeams(value); got.expected != want || got.Pizzas() > problem.M {
+			t.Fatalf("%+v with values %v: got %v, want value %.0f", problem, value, got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/mcieno/HashCode/internal/dashboard"
+	"github.com/mcieno/HashCode/internal/ingredients"
+)
+
+// Represents a pizza as a set of ingredients
+type Pizza struct {
+	id             int             // The sequence ID of this pizza
+	I              int             // The number of ingredints this pizza is made of
+	ingredients    ingredients.Set // The IDs of the ingredients in this pizza
+	ingredientlist []int           // The same IDs, as a list to iterate over
+}
+
+// Represents an instance of the problem
+type Problem struct {
+	M             int                // Number of pizzas available
+	T2            int                // Number of 2-person teams
+	T3            int                // Number of 3-person teams
+	T4            int                // Number of 4-person teams
+	pizzas        []Pizza            // List of pizzas available
+	ingredients   []string           // List of ingredients by name
+	ingredientids map[string]int     // Map from ingredient names to their IDs
+	tracker       *dashboard.Tracker // Where optimisers publish their progress, if anywhere
+	weights       []int              // Weight of each ingredient for the heuristics, nil if all weigh 1
+	class         []int              // Class of identical pizzas each pizza belongs to
+	classes       [][]int            // IDs of the pizzas of each class, in increasing order
+}
+
+// Represents an order, i.e., the pizzas a team will receive
+type Order struct {
+	score    int          // Score of this order
+	pizzaids map[int]bool // Set of pizza IDs in this order
+	counts   map[int]int  // Number of pizzas in this order having each ingredient
+	team     int          // Size of the team the order was declared for, 0 if that of its pizzas
+}
+
+// Represents a solution to the problem, i.e., a list of orders delivered to teams
+type Solution []Order
+
+func Parse(filename string) Problem {
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	lines := strings.Split(string(input), "\n")
+	header, dataset := lines[0], lines[1:]
+
+	var M, T2, T3, T4 int
+	if _, err := fmt.Sscanf(header, "%d %d %d %d", &M, &T2, &T3, &T4); err != nil {
+		panic(err)
+	}
+
+	pizzas := make([]Pizza, M)
+	names := make([]string, 0)
+	ingredientids := make(map[string]int)
+
+	// Pizzas with the same ingredients are interchangeable, so they are
+	// grouped in classes, numbered by their first pizza
+	class := make([]int, M)
+	classes := make([][]int, 0)
+	classids := make(map[string]int)
+
+	for i := range pizzas {
+		pizzainfo := strings.Split(dataset[i], " ")
+
+		var I int
+		if _, err := fmt.Sscanf(pizzainfo[0], "%d", &I); err != nil {
+			panic(err)
+		}
+
+		var pizzaingredients ingredients.Set
+
+		for k := 1; k <= I; k++ {
+			ingredientName := pizzainfo[k]
+			if _, found := ingredientids[ingredientName]; !found {
+				ingredientids[ingredientName] = len(names)
+				names = append(names, ingredientName)
+			}
+
+			pizzaingredients.Add(ingredientids[ingredientName])
+		}
+
+		pizzas[i] = Pizza{
+			id:             i,
+			I:              I,
+			ingredients:    pizzaingredients,
+			ingredientlist: pizzaingredients.List(),
+		}
+
+		key := fmt.Sprint(pizzaingredients)
+		if _, found := classids[key]; !found {
+			classids[key] = len(classes)
+			classes = append(classes, nil)
+		}
+		class[i] = classids[key]
+		classes[class[i]] = append(classes[class[i]], i)
+	}
+
+	return Problem{
+		M:             M,
+		T2:            T2,
+		T3:            T3,
+		T4:            T4,
+		pizzas:        pizzas,
+		ingredients:   names,
+		ingredientids: ingredientids,
+		class:         class,
+		classes:       classes,
+	}
+}
+
+// Returns the number of pizzas identical to one with a lower ID.
+func (problem Problem) Duplicates() int {
+	return problem.M - len(problem.classes)
+}
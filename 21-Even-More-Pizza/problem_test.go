@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseClasses(t *testing.T) {
+	tests := []struct {
+		dataset    string
+		duplicates int
+	}{
+		{"a", 1},
+		{"b", 280},
+		{"c", 0},
+	}
+
+	for _, test := range tests {
+		problem := Parse(fmt.Sprintf("in/%s.txt", test.dataset))
+
+		if got := problem.Duplicates(); got != test.duplicates {
+			t.Errorf("%s: got %d duplicates, want %d", test.dataset, got, test.duplicates)
+		}
+
+		for c, pids := range problem.classes {
+			for k, pid := range pids {
+				if problem.class[pid] != c {
+					t.Errorf("%s: pizza %d is in class %d, listed in %d", test.dataset, pid, problem.class[pid], c)
+				}
+				if k > 0 && pid <= pids[k-1] {
+					t.Errorf("%s: class %d lists pizzas %v out of order", test.dataset, c, pids)
+				}
+			}
+		}
+
+		keys := make([]string, problem.M)
+		for pid, pizza := range problem.pizzas {
+			keys[pid] = fmt.Sprint(pizza.ingredientlist)
+		}
+		for a := range problem.pizzas {
+			for b := a + 1; b < problem.M && b < a+500; b++ {
+				if (keys[a] == keys[b]) != (problem.class[a] == problem.class[b]) {
+					t.Errorf("%s: pizzas %d and %d in classes %d and %d", test.dataset, a, b, problem.class[a], problem.class[b])
+				}
+			}
+		}
+	}
+}
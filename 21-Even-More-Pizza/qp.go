@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formats, in CPLEX LP format, the quadratic program of serving the teams of
+// the given sizes with the given pizzas. Binary variable p<pizza>t<team> tells
+// whether the pizza goes to the team, t<team>g<size> whether the team is served
+// and i<ingredient>t<team> whether the team gets the ingredient. The objective
+// is the sum over the teams of the squared number of their ingredients.
+//
+// The objective has a term for each pair of ingredients of each team, so whole
+// datasets beyond A and B are out of reach: restrict the model to the pizzas
+// and teams of a sub-problem instead.
+func (problem Problem) FormatQP(pids []int, sizes []int) []byte {
+	names := problem.ingredientVariables()
+
+	// Ingredients of the given pizzas, and the pizzas having each of them
+	having := make(map[int][]int)
+	for _, pid := range pids {
+		for _, i := range problem.pizzas[pid].ingredientlist {
+			having[i] = append(having[i], pid)
+		}
+	}
+	ingredients := make([]int, 0, len(having))
+	for i := range having {
+		ingredients = append(ingredients, i)
+	}
+	sort.Ints(ingredients)
+
+	var sb strings.Builder
+
+	sb.WriteString("Maximize\n\n obj: [ ")
+	var terms []string
+	for t := range sizes {
+		for a, i := range ingredients {
+			terms = append(terms, fmt.Sprintf("2 i%st%d ^ 2", names[i], t))
+			for _, j := range ingredients[a+1:] {
+				terms = append(terms, fmt.Sprintf("4 i%st%d * i%st%d", names[i], t, names[j], t))
+			}
+		}
+	}
+	sb.WriteString(strings.Join(terms, " + "))
+	sb.WriteString(" ] / 2\n\n\nSubject To\n\n")
+
+	// Each pizza goes to one team at most
+	for _, pid := range pids {
+		terms = terms[:0]
+		for t := range sizes {
+			terms = append(terms, fmt.Sprintf("p%dt%d", pid, t))
+		}
+		sb.WriteString(fmt.Sprintf(" %s  <= 1\n", strings.Join(terms, " + ")))
+	}
+
+	// Each team gets as many pizzas as people, or none
+	for t, size := range sizes {
+		terms = terms[:0]
+		for _, pid := range pids {
+			terms = append(terms, fmt.Sprintf("p%dt%d", pid, t))
+		}
+		sb.WriteString(fmt.Sprintf(" %s - %d t%dg%d = 0\n", strings.Join(terms, " + "), size, t, size))
+	}
+
+	// A team only gets an ingredient if it gets a pizza having it
+	for _, i := range ingredients {
+		for t := range sizes {
+			sb.WriteString(fmt.Sprintf(" i%st%d", names[i], t))
+			for _, pid := range having[i] {
+				sb.WriteString(fmt.Sprintf(" - p%dt%d", pid, t))
+			}
+			sb.WriteString(" <= 0\n")
+		}
+	}
+
+	sb.WriteString("\nBinaries\n\n")
+	for _, i := range ingredients {
+		for t := range sizes {
+			sb.WriteString(fmt.Sprintf(" i%st%d\n", names[i], t))
+		}
+	}
+	for _, pid := range pids {
+		for t := range sizes {
+			sb.WriteString(fmt.Sprintf(" p%dt%d\n", pid, t))
+		}
+	}
+	for t, size := range sizes {
+		sb.WriteString(fmt.Sprintf(" t%dg%d\n", t, size))
+	}
+	sb.WriteString("\n\nEnd\n")
+
+	return []byte(sb.String())
+}
+
+// Exports the quadratic program of serving the teams of the given sizes with
+// the given pizzas to a file.
+func (problem Problem) ExportQP(pids []int, sizes []int, filename string) {
+	if err := ioutil.WriteFile(filename, problem.FormatQP(pids, sizes), 0644); err != nil {
+		panic(err)
+	}
+}
+
+// Returns the name of each ingredient as used in variable names. LP names
+// cannot contain some characters ingredients have, e.g. '-', which are thus
+// replaced by '_'; should two ingredients end up with the same name, the ID of
+// the latter is appended to it.
+func (problem Problem) ingredientVariables() []string {
+	invalid := regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+	names := make([]string, len(problem.ingredients))
+	taken := make(map[string]bool)
+	for i, ingredient := range problem.ingredients {
+		name := invalid.ReplaceAllString(ingredient, "_")
+		if taken[name] {
+			name = fmt.Sprintf("%s_%d", name, i)
+		}
+		taken[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+var (
+	// A variable in a CPLEX XML solution file
+	qpXMLVariable = regexp.MustCompile(`name="(p\d+t\d+)"[^>]*value="([^"]+)"`)
+	// An assignment variable of the quadratic program
+	qpAssignment = regexp.MustCompile(`^p(\d+)t(\d+)$`)
+)
+
+// Imports the solution of a quadratic program written by FormatQP, as found by
+// a solver: team t gets the pizzas whose p<pizza>t<team> variable is set.
+// CPLEX XML solution files are understood, as well as the text ones most
+// solvers write, where each line holds a variable name followed by its value.
+func (problem Problem) ImportQPSolution(filename string) Solution {
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	values := make(map[string]string)
+	if matches := qpXMLVariable.FindAllStringSubmatch(string(input), -1); len(matches) > 0 {
+		for _, match := range matches {
+			values[match[1]] = match[2]
+		}
+	} else {
+		for _, line := range strings.Split(string(input), "\n") {
+			fields := strings.Fields(line)
+			for k := 0; k+1 < len(fields); k++ {
+				if qpAssignment.MatchString(fields[k]) {
+					values[fields[k]] = fields[k+1]
+					break
+				}
+			}
+		}
+	}
+
+	teams := make(map[int]Order)
+	for name, value := range values {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			panic(fmt.Sprintf("Variable %s has value %q: %v", name, value, err))
+		}
+		if v < 0.5 {
+			continue
+		}
+
+		match := qpAssignment.FindStringSubmatch(name)
+		pid, _ := strconv.Atoi(match[1])
+		t, _ := strconv.Atoi(match[2])
+		if pid >= problem.M {
+			panic(fmt.Sprintf("Pizza %d does not exist", pid))
+		}
+
+		if _, found := teams[t]; !found {
+			teams[t] = NewOrder()
+		}
+		teams[t] = problem.AddPizzaToOrder(pid, teams[t])
+	}
+
+	ts := make([]int, 0, len(teams))
+	for t := range teams {
+		ts = append(ts, t)
+	}
+	sort.Ints(ts)
+
+	solution := make(Solution, 0, len(ts))
+	for _, t := range ts {
+		solution = append(solution, teams[t])
+	}
+
+	return solution
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// a.qp must stay the model FormatQP writes for dataset A.
+func TestFormatQPDatasetA(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	pids := []int{0, 1, 2, 3, 4}
+	sizes := []int{2, 3, 3, 4}
+
+	want, err := ioutil.ReadFile("a.qp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := problem.FormatQP(pids, sizes); string(got) != string(want) {
+		t.Errorf("model differs from a.qp:\n%s", got)
+	}
+}
+
+func TestIngredientVariables(t *testing.T) {
+	problem := Problem{ingredients: []string{"emmental-cheese", "emmental_cheese", "basil"}}
+
+	names := problem.ingredientVariables()
+	want := []string{"emmental_cheese", "emmental_cheese_1", "basil"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("ingredient %d: got %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestImportQPSolution(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	tests := []struct {
+		name     string
+		solution string
+	}{
+		{
+			name:     "text",
+			solution: "Optimal - objective value 148\n  0 p1t1 1 0\n  1 p4t1 1 0\n  2 p0t1 0.9999999 0\n  3 p2t0 1 0\n  4 p3t0 1 0\n  5 p0t0 0 0\n  6 t0g2 1 0\n",
+		},
+		{
+			name: "cplex",
+			solution: `<?xml version = "1.0" standalone="yes"?>
+<CPLEXSolution version="1.2">
+ <variables>
+  <variable name="p0t0" index="0" value="0"/>
+  <variable name="p0t1" index="1" value="1"/>
+  <variable name="p1t1" index="2" value="1"/>
+  <variable name="p2t0" index="3" value="1"/>
+  <variable name="p3t0" index="4" value="1"/>
+  <variable name="p4t1" index="5" value="1"/>
+  <variable name="t0g2" index="6" value="1"/>
+ </variables>
+</CPLEXSolution>
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "solution")
+			if err := ioutil.WriteFile(filename, []byte(test.solution), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			solution := problem.ImportQPSolution(filename)
+			if len(solution) != 2 {
+				t.Fatalf("got %d orders, want 2", len(solution))
+			}
+
+			// Team 0 gets pizzas 2 and 3, team 1 pizzas 0, 1 and 4
+			for k, want := range [][]int{{2, 3}, {0, 1, 4}} {
+				if got := pizzasOf(solution[k]); !reflect.DeepEqual(got, want) {
+					t.Errorf("team %d: got pizzas %v, want %v", k, got, want)
+				}
+			}
+
+			if score := solution.Score(); score != 25+49 {
+				t.Errorf("got score %d, want %d", score, 25+49)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Diagnostics of an order of a solution.
+type OrderReport struct {
+	Order       int // Index of the order in the solution
+	Size        int // Number of pizzas, i.e., of people in the team
+	Ingredients int // Number of distinct ingredients
+	Wasted      int // Ingredients delivered more than once, counting every extra copy
+	Score       int // Score of the order
+}
+
+// Diagnostics of a solution.
+type Report struct {
+	Orders   []OrderReport
+	Score    int         // Score of the solution
+	Wasted   int         // Ingredients wasted by all the orders
+	Unused   int         // Number of pizzas nobody gets
+	Pizzas   int         // Number of pizzas of the problem
+	Unserved map[int]int // Number of teams of each size nobody serves
+	Teams    map[int]int // Number of teams of each size
+}
+
+// A range of scores of the histogram, with the number of orders scoring within
+// it.
+type Bin struct {
+	From, To int // Bounds of the range, both included
+	Count    int // Number of orders in the range
+}
+
+// Returns the diagnostics of the solution.
+func (problem Problem) Report(solution Solution) Report {
+	rT2, rT3, rT4, rpizzaids := problem.Remaining(solution)
+
+	report := Report{
+		Orders:   make([]OrderReport, len(solution)),
+		Score:    solution.Score(),
+		Unused:   len(rpizzaids),
+		Pizzas:   problem.M,
+		Unserved: map[int]int{2: rT2, 3: rT3, 4: rT4},
+		Teams:    map[int]int{2: problem.T2, 3: problem.T3, 4: problem.T4},
+	}
+
+	for i, order := range solution {
+		wasted := 0
+		for _, count := range order.counts {
+			wasted += count - 1
+		}
+
+		report.Orders[i] = OrderReport{
+			Order:       i,
+			Size:        len(order.pizzaids),
+			Ingredients: len(order.counts),
+			Wasted:      wasted,
+			Score:       order.score,
+		}
+		report.Wasted += wasted
+	}
+
+	return report
+}
+
+// Returns the histogram of the scores of the orders, in `bins` ranges of the
+// same width between the lowest and the highest score. Fewer ranges are used
+// if the scores do not span as many values.
+func (report Report) Histogram(bins int) []Bin {
+	if len(report.Orders) == 0 || bins < 1 {
+		return nil
+	}
+
+	lo, hi := report.Orders[0].Score, report.Orders[0].Score
+	for _, order := range report.Orders {
+		lo, hi = min(lo, order.Score), max(hi, order.Score)
+	}
+
+	width := (hi - lo + bins) / bins
+	histogram := make([]Bin, (hi-lo)/width+1)
+	for b := range histogram {
+		histogram[b] = Bin{From: lo + b*width, To: lo + (b+1)*width - 1}
+	}
+	for _, order := range report.Orders {
+		histogram[(order.Score-lo)/width].Count++
+	}
+
+	return histogram
+}
+
+// Writes the report as text: a table of the orders followed by the aggregates,
+// with the histogram of the scores in `bins` ranges.
+func (report Report) WriteText(w io.Writer, bins int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "order\tsize\tingredients\twasted\tscore\t")
+	for _, order := range report.Orders {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t\n", order.Order, order.Size, order.Ingredients, order.Wasted, order.Score)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n[*] Orders: %d, score %d\n", len(report.Orders), report.Score))
+	sb.WriteString(fmt.Sprintf("[*] Wasted ingredients: %d\n", report.Wasted))
+	sb.WriteString(fmt.Sprintf("[*] Unused pizzas: %d/%d\n", report.Unused, report.Pizzas))
+	for size := 2; size <= 4; size++ {
+		sb.WriteString(fmt.Sprintf("[*] %d-person teams: %d/%d unserved\n", size, report.Unserved[size], report.Teams[size]))
+	}
+
+	histogram := report.Histogram(bins)
+	if len(histogram) > 0 {
+		sb.WriteString("[*] Scores of the orders:\n")
+	}
+
+	most := 0
+	for _, bin := range histogram {
+		most = max(most, bin.Count)
+	}
+	for _, bin := range histogram {
+		bar := strings.Repeat("#", 40*bin.Count/most)
+		sb.WriteString(strings.TrimRight(fmt.Sprintf("%12d - %-12d %8d %s", bin.From, bin.To, bin.Count, bar), " ") + "\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Writes the orders of the report as CSV, with a header line.
+func (report Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"order", "size", "ingredients", "wasted", "score"})
+	for _, order := range report.Orders {
+		cw.Write([]string{
+			strconv.Itoa(order.Order),
+			strconv.Itoa(order.Size),
+			strconv.Itoa(order.Ingredients),
+			strconv.Itoa(order.Wasted),
+			strconv.Itoa(order.Score),
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReportDatasetA(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solution, _ := problem.Import("out/a.txt")
+
+	report := problem.Report(solution)
+
+	want := Report{
+		Orders: []OrderReport{
+			{Order: 0, Size: 3, Ingredients: 7, Wasted: 1, Score: 49},
+			{Order: 1, Size: 2, Ingredients: 5, Wasted: 1, Score: 25},
+		},
+		Score:    74,
+		Wasted:   2,
+		Unused:   0,
+		Pizzas:   5,
+		Unserved: map[int]int{2: 0, 3: 1, 4: 1},
+		Teams:    map[int]int{2: 1, 3: 2, 4: 1},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %+v, want %+v", report, want)
+	}
+
+	var csv bytes.Buffer
+	if err := report.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if want := "order,size,ingredients,wasted,score\n0,3,7,1,49\n1,2,5,1,25\n"; csv.String() != want {
+		t.Errorf("got CSV %q, want %q", csv.String(), want)
+	}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text, 10); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"[*] Orders: 2, score 74", "[*] 3-person teams: 1/2 unserved"} {
+		if !strings.Contains(text.String(), line) {
+			t.Errorf("text report lacks %q:\n%s", line, text.String())
+		}
+	}
+}
+
+func TestReportHistogram(t *testing.T) {
+	report := Report{}
+	for _, score := range []int{4, 9, 9, 16, 25, 100} {
+		report.Orders = append(report.Orders, OrderReport{Score: score})
+	}
+
+	tests := []struct {
+		bins int
+		want []Bin
+	}{
+		{1, []Bin{{4, 100, 6}}},
+		{4, []Bin{{4, 28, 5}, {29, 53, 0}, {54, 78, 0}, {79, 103, 1}}},
+	}
+
+	for _, test := range tests {
+		if got := report.Histogram(test.bins); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d bins: got %v, want %v", test.bins, got, test.want)
+		}
+	}
+
+	if got := (Report{}).Histogram(10); got != nil {
+		t.Errorf("no orders: got %v, want none", got)
+	}
+}
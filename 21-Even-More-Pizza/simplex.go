@@ -0,0 +1,98 @@
+package main
+
+const (
+	simplexEpsilon = 1e-9 // Pivoting tolerance of the simplex method
+	maxDegenerate  = 50   // Degenerate pivots in a row before switching to Bland's rule
+)
+
+// Returns the maximum of c·x subject to A·x <= b and x >= 0, where b >= 0 so
+// that x = 0 is feasible, using the simplex method on a dense tableau. The
+// entering variable is the one with the largest reduced cost, except after a
+// long run of degenerate pivots, which the programs UpperBound writes have
+// plenty of: then Bland's rule, which never cycles, is used until the objective
+// improves again. Returns false if the program is unbounded.
+func maximize(c []float64, A [][]float64, b []float64) (float64, bool) {
+	m, n := len(A), len(c)
+
+	// Each row holds the coefficients of the n variables, of the m slacks and
+	// the right-hand side; the last row holds the reduced costs
+	tableau := make([][]float64, m+1)
+	for i := range tableau {
+		tableau[i] = make([]float64, n+m+1)
+	}
+	basis := make([]int, m)
+	for i := 0; i < m; i++ {
+		copy(tableau[i], A[i])
+		tableau[i][n+i] = 1
+		tableau[i][n+m] = b[i]
+		basis[i] = n + i
+	}
+	for j := 0; j < n; j++ {
+		tableau[m][j] = -c[j]
+	}
+
+	degenerate := 0 // Pivots in a row that did not improve the objective
+	for {
+		bland := degenerate > maxDegenerate
+
+		entering := -1
+		for j := 0; j < n+m; j++ {
+			if cost := tableau[m][j]; cost < -simplexEpsilon && (entering == -1 || !bland && cost < tableau[m][entering]) {
+				entering = j
+				if bland {
+					break
+				}
+			}
+		}
+
+		if entering == -1 {
+			return tableau[m][n+m], true
+		}
+
+		leaving := -1
+		var ratio float64
+		for i := 0; i < m; i++ {
+			if a := tableau[i][entering]; a > simplexEpsilon {
+				r := tableau[i][n+m] / a
+				if leaving == -1 || r < ratio-simplexEpsilon || (r < ratio+simplexEpsilon && basis[i] < basis[leaving]) {
+					leaving, ratio = i, r
+				}
+			}
+		}
+
+		if leaving == -1 {
+			return 0, false
+		}
+
+		if ratio > simplexEpsilon {
+			degenerate = 0
+		} else {
+			degenerate++
+		}
+
+		pivot(tableau, leaving, entering)
+		basis[leaving] = entering
+	}
+}
+
+// Pivots the tableau on row `r` and column `k`.
+func pivot(tableau [][]float64, r, k int) {
+	row := tableau[r]
+	scale := row[k]
+	for j := range row {
+		row[j] /= scale
+	}
+
+	for i, other := range tableau {
+		if i == r || other[k] == 0 {
+			continue
+		}
+
+		f := other[k]
+		for j, a := range row {
+			if a != 0 {
+				other[j] -= f * a
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaximize(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       []float64
+		A       [][]float64
+		b       []float64
+		value   float64
+		bounded bool
+	}{
+		{
+			name:    "textbook",
+			c:       []float64{3, 5},
+			A:       [][]float64{{1, 0}, {0, 2}, {3, 2}},
+			b:       []float64{4, 12, 18},
+			value:   36,
+			bounded: true,
+		},
+		{
+			// Beale's example, which cycles with the largest reduced cost
+			// and no anti-cycling rule
+			name: "degenerate",
+			c:    []float64{0.75, -20, 0.5, -6},
+			A: [][]float64{
+				{0.25, -8, -1, 9},
+				{0.5, -12, -0.5, 3},
+				{0, 0, 1, 0},
+			},
+			b:       []float64{0, 0, 1},
+			value:   1.25,
+			bounded: true,
+		},
+		{
+			name:    "nothing to gain",
+			c:       []float64{-1, -2},
+			A:       [][]float64{{1, 1}},
+			b:       []float64{5},
+			value:   0,
+			bounded: true,
+		},
+		{
+			name:    "unbounded",
+			c:       []float64{1, 1},
+			A:       [][]float64{{1, -1}},
+			b:       []float64{3},
+			bounded: false,
+		},
+	}
+
+	for _, test := range tests {
+		value, bounded := maximize(test.c, test.A, test.b)
+		if bounded != test.bounded || bounded && math.Abs(value-test.value) > 1e-6 {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, value, bounded, test.value, test.bounded)
+		}
+	}
+}
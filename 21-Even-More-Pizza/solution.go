@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Formats the solution in the output format.
+func (problem *Problem) Format(solution Solution) []byte {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d\n", len(solution)))
+
+	for _, order := range solution {
+		sb.WriteString(fmt.Sprint(len(order.pizzaids)))
+		for pid := range order.pizzaids {
+			sb.WriteString(fmt.Sprintf(" %d", pid))
+		}
+		sb.WriteString("\n")
+	}
+
+	return []byte(sb.String())
+}
+
+// Exports the solution to a file.
+func (problem *Problem) Export(solution Solution, filename string) {
+	if err := ioutil.WriteFile(filename, problem.Format(solution), 0644); err != nil {
+		panic(err)
+	}
+}
+
+func (problem Problem) Import(filename string) (Solution, int) {
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	lines := strings.Split(string(input), "\n")
+	header, dataset := lines[0], lines[1:]
+
+	var T int
+	if _, err := fmt.Sscanf(header, "%d", &T); err != nil {
+		panic(err)
+	}
+
+	solution := make(Solution, T)
+
+	for i := 0; i < T; i++ {
+		orderinfo := strings.Split(dataset[i], " ")
+
+		var Tsize int
+		if _, err := fmt.Sscanf(orderinfo[0], "%d", &Tsize); err != nil {
+			panic(err)
+		}
+
+		solution[i] = NewOrder()
+
+		for k := 1; k <= Tsize; k++ {
+			var pid int
+			if _, err := fmt.Sscanf(orderinfo[k], "%d", &pid); err != nil {
+				panic(err)
+			}
+			solution[i] = problem.AddPizzaToOrder(pid, solution[i])
+		}
+	}
+
+	score := 0
+	for _, order := range solution {
+		score += order.score
+	}
+
+	return solution, score
+}
+
+// Returns the score of a solution
+func (solution Solution) Score() int {
+	score := 0
+	for _, order := range solution {
+		score += order.score
+	}
+
+	return score
+}
+
+// Deep copies a solution
+func (solution Solution) Clone() Solution {
+	clone := make(Solution, len(solution))
+	for i, order := range solution {
+		clone[i] = order.Clone()
+	}
+
+	return clone
+}
+
+// Returns the number of remaining n-person teams and the set of remaining pizzas
+func (problem Problem) Remaining(solution Solution) (int, int, int, map[int]bool) {
+	rT2, rT3, rT4 := problem.T2, problem.T3, problem.T4
+
+	upizzaids := make(map[int]bool)
+	for _, order := range solution {
+		switch len(order.pizzaids) {
+		case 2:
+			rT2--
+		case 3:
+			rT3--
+		case 4:
+			rT4--
+		}
+		for pid := range order.pizzaids {
+			upizzaids[pid] = true
+		}
+	}
+
+	rpizzaids := make(map[int]bool)
+	for _, pizza := range problem.pizzas {
+		if exists, found := upizzaids[pizza.id]; !found || !exists {
+			rpizzaids[pizza.id] = true
+		}
+	}
+
+	return rT2, rT3, rT4, rpizzaids
+}
+
+// Returns the number of teams of the given size (2, 3 or 4) in the problem.
+func (problem Problem) Teams(size int) int {
+	switch size {
+	case 2:
+		return problem.T2
+	case 3:
+		return problem.T3
+	case 4:
+		return problem.T4
+	}
+
+	return 0
+}
+
+// Returns, by team size, the number of orders in the solution and their total
+// score. Orders for teams of unexpected sizes are ignored.
+func (solution Solution) Breakdown() (map[int]int, map[int]int) {
+	served := map[int]int{2: 0, 3: 0, 4: 0}
+	scores := map[int]int{2: 0, 3: 0, 4: 0}
+	for _, order := range solution {
+		if size := order.Team(); size >= 2 && size <= 4 {
+			served[size]++
+			scores[size] += order.score
+		}
+	}
+
+	return served, scores
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// A rule of the problem broken by a submission.
+type Violation struct {
+	line    int    // Line of the submission the violation was found at
+	message string // What is wrong
+}
+
+func (violation Violation) String() string {
+	return fmt.Sprintf("line %d: %s", violation.line, violation.message)
+}
+
+// Checks a submission against the rules of the problem. Unlike Import, it
+// does not trust the file: it reports every violation it finds, along with
+// the line it was found at, and keeps going.
+// Returns the orders that could be read, even if invalid, and the violations.
+func (problem Problem) Validate(filename string) (Solution, []Violation) {
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(input), "\n"), "\n")
+
+	violations := make([]Violation, 0)
+	violate := func(line int, format string, args ...interface{}) {
+		violations = append(violations, Violation{line: line, message: fmt.Sprintf(format, args...)})
+	}
+
+	D, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil || D < 0 {
+		violate(1, "expected the number of delivered orders, got %q", lines[0])
+		return Solution{}, violations
+	}
+
+	if len(lines)-1 != D {
+		violate(1, "%d orders declared, %d found", D, len(lines)-1)
+	}
+
+	solution := make(Solution, 0, D)
+	deliveredat := make(map[int]int)        // Line each pizza was first delivered at
+	served := map[int]int{2: 0, 3: 0, 4: 0} // Orders delivered by team size
+
+	for k, orderline := range lines[1:] {
+		line := k + 2
+
+		fields := strings.Fields(orderline)
+		if len(fields) == 0 {
+			violate(line, "empty line")
+			continue
+		}
+
+		size, err := strconv.Atoi(fields[0])
+		if err != nil {
+			violate(line, "expected the team size, got %q", fields[0])
+			continue
+		}
+
+		if size < 2 || size > 4 {
+			violate(line, "team size must be 2, 3 or 4, got %d", size)
+		}
+
+		if len(fields)-1 != size {
+			violate(line, "team of %d people gets %d pizzas", size, len(fields)-1)
+		}
+
+		order := NewOrder()
+		order.team = size
+		for _, field := range fields[1:] {
+			pid, err := strconv.Atoi(field)
+			if err != nil {
+				violate(line, "expected a pizza ID, got %q", field)
+				continue
+			}
+
+			if pid < 0 || pid >= problem.M {
+				violate(line, "pizza %d does not exist", pid)
+				continue
+			}
+
+			if first, found := deliveredat[pid]; found {
+				violate(line, "pizza %d already delivered at line %d", pid, first)
+				continue
+			}
+
+			deliveredat[pid] = line
+			order = problem.AddPizzaToOrder(pid, order)
+		}
+
+		if size >= 2 && size <= 4 {
+			if served[size]++; served[size] > problem.Teams(size) {
+				violate(line, "only %d teams of %d people exist", problem.Teams(size), size)
+			}
+		}
+
+		solution = append(solution, order)
+	}
+
+	return solution, violations
+}
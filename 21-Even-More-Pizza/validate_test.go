@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	problem := Parse("in/a.txt") // 5 pizzas, one 2-person team, two 3-person and one 4-person
+
+	tests := []struct {
+		name       string
+		submission string
+		violations []string
+		score      int
+		served     map[int]int // Orders by declared team size
+	}{
+		{
+			name:       "sample submission",
+			submission: "2\n3 1 4 0\n2 3 2\n",
+			violations: []string{},
+			score:      49 + 25,
+			served:     map[int]int{2: 1, 3: 1, 4: 0},
+		},
+		{
+			name:       "missing orders",
+			submission: "3\n3 1 4 0\n2 3 2\n",
+			violations: []string{"line 1: 3 orders declared, 2 found"},
+			score:      49 + 25,
+			served:     map[int]int{2: 1, 3: 1, 4: 0},
+		},
+		{
+			name:       "unknown pizza",
+			submission: "1\n2 1 5\n",
+			violations: []string{"line 2: pizza 5 does not exist"},
+			score:      9,
+			served:     map[int]int{2: 1, 3: 0, 4: 0},
+		},
+		{
+			name:       "pizza delivered twice",
+			submission: "2\n2 1 4\n3 0 1 3\n",
+			violations: []string{"line 3: pizza 1 already delivered at line 2"},
+			score:      16 + 36,
+			served:     map[int]int{2: 1, 3: 1, 4: 0},
+		},
+		{
+			name:       "bad team size",
+			submission: "1\n1 1\n",
+			violations: []string{"line 2: team size must be 2, 3 or 4, got 1"},
+			score:      9,
+			served:     map[int]int{2: 0, 3: 0, 4: 0},
+		},
+		{
+			name:       "size mismatch",
+			submission: "1\n3 1 4\n",
+			violations: []string{"line 2: team of 3 people gets 2 pizzas"},
+			score:      16,
+			served:     map[int]int{2: 0, 3: 1, 4: 0},
+		},
+		{
+			name:       "too many teams of a size",
+			submission: "2\n2 1 4\n2 0 2\n",
+			violations: []string{"line 3: only 1 teams of 2 people exist"},
+			score:      16 + 25,
+			served:     map[int]int{2: 2, 3: 0, 4: 0},
+		},
+		{
+			name:       "malformed line",
+			submission: "1\nfour 1 2 3 4\n",
+			violations: []string{`line 2: expected the team size, got "four"`},
+			score:      0,
+			served:     map[int]int{2: 0, 3: 0, 4: 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "out.txt")
+			if err := ioutil.WriteFile(filename, []byte(test.submission), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			solution, violations := problem.Validate(filename)
+
+			messages := make([]string, len(violations))
+			for i, violation := range violations {
+				messages[i] = violation.String()
+			}
+
+			if !reflect.DeepEqual(messages, test.violations) {
+				t.Errorf("got violations %q, want %q", messages, test.violations)
+			}
+
+			if score := solution.Score(); score != test.score {
+				t.Errorf("got score %d, want %d", score, test.score)
+			}
+
+			if served, _ := solution.Breakdown(); !reflect.DeepEqual(served, test.served) {
+				t.Errorf("got served teams %v, want %v", served, test.served)
+			}
+		})
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solution, _ := problem.Import("out/a.txt")
+
+	rT2, rT3, rT4, rpizzaids := problem.Remaining(solution)
+	if rT2 != 0 || rT3 != 1 || rT4 != 1 {
+		t.Errorf("got %d, %d, %d remaining teams, want 0, 1, 1", rT2, rT3, rT4)
+	}
+
+	if len(rpizzaids) != 0 {
+		t.Errorf("got remaining pizzas %v, want none", rpizzaids)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ways of weighting ingredients.
+const (
+	WeightingUniform = "uniform" // Every ingredient weighs the same
+	WeightingInverse = "inverse" // Ingredients weigh the inverse of their frequency
+	WeightingLog     = "log"     // Ingredients weigh the logarithm of the inverse of their frequency
+)
+
+// Weights are fixed-point numbers with this many units per ingredient on every
+// pizza, so that gains stay integers.
+const weightUnit = 1000
+
+// Returns the weight of each ingredient according to the `scheme`. Ingredients
+// on few pizzas are what makes orders score more than others, so all schemes
+// but uniform favour them.
+func (problem Problem) IngredientWeights(scheme string) ([]int, error) {
+	frequency := make([]int, len(problem.ingredients))
+	for _, pizza := range problem.pizzas {
+		for _, i := range pizza.ingredientlist {
+			frequency[i]++
+		}
+	}
+
+	weights := make([]int, len(problem.ingredients))
+	for i, f := range frequency {
+		switch scheme {
+		case WeightingUniform:
+			weights[i] = 1
+		case WeightingInverse:
+			weights[i] = weightUnit * problem.M / f
+		case WeightingLog:
+			weights[i] = int(math.Round(weightUnit * math.Log(1+float64(problem.M)/float64(f))))
+		default:
+			return nil, fmt.Errorf("unknown weighting scheme %q", scheme)
+		}
+	}
+
+	return weights, nil
+}
+
+// Returns the weight of ingredient `i`.
+func (problem Problem) weight(i int) int {
+	if problem.weights == nil {
+		return 1
+	}
+
+	return problem.weights[i]
+}
+
+// Returns the weight of the ingredients of pizza `pid`.
+func (problem Problem) Weight(pid int) int {
+	if problem.weights == nil {
+		return len(problem.pizzas[pid].ingredientlist)
+	}
+
+	weight := 0
+	for _, i := range problem.pizzas[pid].ingredientlist {
+		weight += problem.weights[i]
+	}
+
+	return weight
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIngredientWeights(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	frequency := make([]int, len(problem.ingredients))
+	for _, pizza := range problem.pizzas {
+		for _, i := range pizza.ingredientlist {
+			frequency[i]++
+		}
+	}
+
+	for _, scheme := range []string{WeightingInverse, WeightingLog} {
+		weights, err := problem.IngredientWeights(scheme)
+		if err != nil {
+			t.Fatalf("%s: %v", scheme, err)
+		}
+
+		for i := range weights {
+			for j := range weights {
+				if frequency[i] < frequency[j] && weights[i] <= weights[j] {
+					t.Errorf("%s: %s on %d pizzas weighs %d, %s on %d weighs %d",
+						scheme, problem.ingredients[i], frequency[i], weights[i],
+						problem.ingredients[j], frequency[j], weights[j])
+				}
+			}
+		}
+	}
+
+	weights, err := problem.IngredientWeights(WeightingUniform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, weight := range weights {
+		if weight != 1 {
+			t.Errorf("uniform: %s weighs %d, want 1", problem.ingredients[i], weight)
+		}
+	}
+
+	if _, err := problem.IngredientWeights("nope"); err == nil {
+		t.Error("unknown scheme: got no error")
+	}
+}
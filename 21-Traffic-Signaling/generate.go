@@ -0,0 +1,331 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+const (
+	TopologyGrid   = "grid"   // Intersections on a grid, two-way streets between neighbours
+	TopologyRadial = "radial" // Rings of intersections around a centre, connected by spokes
+	TopologyRandom = "random" // A random one-way loop through every intersection
+
+	LengthsUniform     = "uniform"     // Street lengths uniformly distributed in [Lmin, Lmax]
+	LengthsExponential = "exponential" // Mostly short streets, a few up to Lmax
+)
+
+// Parameters of a randomly generated instance of the problem.
+type GeneratorConfig struct {
+	D        int    // Duration of the simulation
+	I        int    // Number of Intersections
+	S        int    // Number of Streets
+	V        int    // Number of Vehicles
+	F        int    // Bonus points for reaching destination
+	Lmin     int    // Minimum length of a street
+	Lmax     int    // Maximum length of a street
+	Lengths  string // Distribution of the street lengths
+	Topology string // Shape of the base city graph
+	Seed     int64  // Seed of the random number generator
+}
+
+// Generates a random, valid instance of the problem. The city is made of a
+// strongly connected base graph of the chosen topology plus random one-way
+// streets up to S. Each vehicle drives the shortest path between two random
+// intersections, entering the first one from a random incoming street.
+func Generate(config GeneratorConfig) (Problem, error) {
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	if config.I < 2 {
+		return Problem{}, fmt.Errorf("at least 2 intersections are required, got %d", config.I)
+	}
+
+	if config.Lmin < 1 || config.Lmax < config.Lmin || config.Lmax > config.D {
+		return Problem{}, fmt.Errorf("invalid street lengths [%d, %d] for D=%d", config.Lmin, config.Lmax, config.D)
+	}
+
+	var edges [][2]int
+	switch config.Topology {
+	case TopologyGrid:
+		edges = gridEdges(config.I)
+	case TopologyRadial:
+		edges = radialEdges(config.I)
+	case TopologyRandom:
+		edges = loopEdges(config.I, rng)
+	default:
+		return Problem{}, fmt.Errorf("unknown topology %q", config.Topology)
+	}
+
+	if config.S < len(edges) {
+		return Problem{}, fmt.Errorf("a %s city of %d intersections needs at least %d streets, got %d", config.Topology, config.I, len(edges), config.S)
+	}
+
+	if config.S > config.I*(config.I-1) {
+		return Problem{}, fmt.Errorf("at most %d streets fit between %d intersections, got %d", config.I*(config.I-1), config.I, config.S)
+	}
+
+	exists := make(map[[2]int]bool)
+	for _, edge := range edges {
+		exists[edge] = true
+	}
+
+	for len(edges) < config.S {
+		edge := [2]int{rng.Intn(config.I), rng.Intn(config.I)}
+		if edge[0] != edge[1] && !exists[edge] {
+			exists[edge] = true
+			edges = append(edges, edge)
+		}
+	}
+
+	problem := Problem{
+		D:             config.D,
+		I:             config.I,
+		S:             config.S,
+		V:             config.V,
+		F:             config.F,
+		streets:       make([]Street, config.S),
+		streetids:     make(map[string]int),
+		intersections: make([]Intersection, config.I),
+		vehicles:      make([]Vehicle, config.V),
+	}
+
+	for iid := range problem.intersections {
+		problem.intersections[iid] = Intersection{
+			id:       iid,
+			incoming: make([]int, 0),
+			outgoing: make([]int, 0),
+		}
+	}
+
+	for sid, edge := range edges {
+		var L int
+		switch config.Lengths {
+		case LengthsUniform:
+			L = config.Lmin + rng.Intn(config.Lmax-config.Lmin+1)
+		case LengthsExponential:
+			L = config.Lmin + int(rng.ExpFloat64()*float64(config.Lmax-config.Lmin)/4)
+			if L > config.Lmax {
+				L = config.Lmax
+			}
+		default:
+			return Problem{}, fmt.Errorf("unknown street length distribution %q", config.Lengths)
+		}
+
+		st := Street{
+			id:   sid,
+			B:    edge[0],
+			E:    edge[1],
+			L:    L,
+			name: intersectionName(edge[0]) + "-" + intersectionName(edge[1]),
+		}
+
+		problem.streets[sid] = st
+		problem.streetids[st.name] = sid
+		problem.intersections[st.E].incoming = append(problem.intersections[st.E].incoming, sid)
+		problem.intersections[st.B].outgoing = append(problem.intersections[st.B].outgoing, sid)
+	}
+
+	for vid := range problem.vehicles {
+		path, found := []int(nil), false
+		for attempt := 0; attempt < 1000 && !found; attempt++ {
+			path, found = problem.randomPath(rng)
+		}
+
+		if !found {
+			return Problem{}, fmt.Errorf("no path of at most %d seconds found for vehicle %d", config.D, vid)
+		}
+
+		problem.vehicles[vid] = Vehicle{id: vid, path: path}
+	}
+
+	return problem, nil
+}
+
+// Builds the path of a vehicle driving the shortest path between two random
+// intersections. Returns false if the path cannot be driven within D seconds.
+func (problem Problem) randomPath(rng *rand.Rand) ([]int, bool) {
+	from, to := rng.Intn(problem.I), rng.Intn(problem.I)
+	if from == to {
+		return nil, false
+	}
+
+	incoming := problem.intersections[from].incoming
+	path := []int{incoming[rng.Intn(len(incoming))]}
+
+	route, length := problem.ShortestPath(from, to)
+	if route == nil || length > problem.D || len(route) >= 1000 {
+		return nil, false
+	}
+
+	return append(path, route...), true
+}
+
+// Returns the street IDs of the shortest path from intersection `from` to
+// intersection `to` and its length, or nil if `to` cannot be reached.
+func (problem Problem) ShortestPath(from, to int) ([]int, int) {
+	dist := make([]int, problem.I)
+	via := make([]int, problem.I) // Street used to reach each intersection
+	for iid := range dist {
+		dist[iid] = math.MaxInt64
+		via[iid] = -1
+	}
+	dist[from] = 0
+
+	queue := &distanceQueue{{iid: from, dist: 0}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(distanceItem)
+		if current.dist > dist[current.iid] {
+			continue
+		}
+
+		if current.iid == to {
+			break
+		}
+
+		for _, sid := range problem.intersections[current.iid].outgoing {
+			st := problem.streets[sid]
+			if d := current.dist + st.L; d < dist[st.E] {
+				dist[st.E] = d
+				via[st.E] = sid
+				heap.Push(queue, distanceItem{iid: st.E, dist: d})
+			}
+		}
+	}
+
+	if dist[to] == math.MaxInt64 {
+		return nil, 0
+	}
+
+	path := make([]int, 0)
+	for iid := to; iid != from; iid = problem.streets[via[iid]].B {
+		path = append(path, via[iid])
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, dist[to]
+}
+
+// Writes the problem to a file in the input format.
+func (problem *Problem) WriteInput(filename string) {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%d %d %d %d %d\n", problem.D, problem.I, problem.S, problem.V, problem.F)
+
+	for _, st := range problem.streets {
+		fmt.Fprintf(&sb, "%d %d %s %d\n", st.B, st.E, st.name, st.L)
+	}
+
+	for _, vehicle := range problem.vehicles {
+		fmt.Fprint(&sb, len(vehicle.path))
+		for _, sid := range vehicle.path {
+			fmt.Fprint(&sb, " ", problem.streets[sid].name)
+		}
+		fmt.Fprintln(&sb)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(sb.String()), 0644); err != nil {
+		panic(err)
+	}
+}
+
+// Names an intersection by spelling its ID with letters, like the official
+// datasets do (e.g., 2021 becomes "cacb").
+func intersectionName(iid int) string {
+	digits := []byte(fmt.Sprint(iid))
+	for i := range digits {
+		digits[i] = 'a' + digits[i] - '0'
+	}
+
+	return string(digits)
+}
+
+// Returns the two-way streets of a grid with `n` intersections filled row by
+// row.
+func gridEdges(n int) [][2]int {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+
+	edges := make([][2]int, 0)
+	for i := 0; i < n; i++ {
+		if right := i + 1; right%cols != 0 && right < n {
+			edges = append(edges, [2]int{i, right}, [2]int{right, i})
+		}
+
+		if down := i + cols; down < n {
+			edges = append(edges, [2]int{i, down}, [2]int{down, i})
+		}
+	}
+
+	return edges
+}
+
+// Returns the two-way streets of a radial city with `n` intersections: a
+// centre (intersection 0) surrounded by rings, each ring being a loop and each
+// spoke joining an intersection to the one in the same position on the next
+// ring.
+func radialEdges(n int) [][2]int {
+	spokes := int(math.Sqrt(float64(n - 1)))
+	if spokes < 3 {
+		spokes = 3
+	}
+
+	edges := make([][2]int, 0)
+	for i := 1; i < n; i++ {
+		ring, spoke := (i-1)/spokes, (i-1)%spokes
+
+		inner := 0
+		if ring > 0 {
+			inner = i - spokes
+		}
+		edges = append(edges, [2]int{i, inner}, [2]int{inner, i})
+
+		// Link to the next intersection on the same ring, closing the loop
+		// once the ring is complete
+		if spoke < spokes-1 && i+1 < n {
+			edges = append(edges, [2]int{i, i + 1}, [2]int{i + 1, i})
+		} else if spoke == spokes-1 {
+			edges = append(edges, [2]int{i, i + 1 - spokes}, [2]int{i + 1 - spokes, i})
+		}
+	}
+
+	return edges
+}
+
+// Returns the one-way streets of a loop visiting all `n` intersections in
+// random order.
+func loopEdges(n int, rng *rand.Rand) [][2]int {
+	order := rng.Perm(n)
+
+	edges := make([][2]int, n)
+	for i := range order {
+		edges[i] = [2]int{order[i], order[(i+1)%n]}
+	}
+
+	return edges
+}
+
+// An intersection reached with a certain tentative distance, used by
+// ShortestPath.
+type distanceItem struct {
+	iid  int
+	dist int
+}
+
+// A min-heap of intersections by distance.
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x interface{}) { *q = append(*q, x.(distanceItem)) }
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
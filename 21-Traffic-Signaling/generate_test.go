@@ -0,0 +1,150 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A small instance of every topology and street length distribution.
+func generatorConfigs() []GeneratorConfig {
+	var configs []GeneratorConfig
+	for _, topology := range []string{TopologyGrid, TopologyRadial, TopologyRandom} {
+		for _, lengths := range []string{LengthsUniform, LengthsExponential} {
+			configs = append(configs, GeneratorConfig{
+				D: 50, I: 12, S: 60, V: 20, F: 100,
+				Lmin: 1, Lmax: 5, Lengths: lengths,
+				Topology: topology,
+				Seed:     1,
+			})
+		}
+	}
+
+	return configs
+}
+
+func generate(t *testing.T, config GeneratorConfig) Problem {
+	t.Helper()
+
+	problem, err := Generate(config)
+	if err != nil {
+		t.Fatalf("%+v: %v", config, err)
+	}
+
+	return problem
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	for _, config := range generatorConfigs() {
+		if !reflect.DeepEqual(generate(t, config), generate(t, config)) {
+			t.Errorf("%s/%s: two instances generated with seed %d differ", config.Topology, config.Lengths, config.Seed)
+		}
+
+		other := config
+		other.Seed++
+		if reflect.DeepEqual(generate(t, config), generate(t, other)) {
+			t.Errorf("%s/%s: seeds %d and %d generated the same instance", config.Topology, config.Lengths, config.Seed, other.Seed)
+		}
+	}
+}
+
+// Generated instances must follow the rules of the input and parse back as
+// they were written.
+func TestGenerateRoundTrip(t *testing.T) {
+	for _, config := range generatorConfigs() {
+		problem := generate(t, config)
+		name := config.Topology + "/" + config.Lengths
+
+		if len(problem.streets) != config.S || len(problem.vehicles) != config.V {
+			t.Fatalf("%s: got %d streets and %d vehicles, want %d and %d", name, len(problem.streets), len(problem.vehicles), config.S, config.V)
+		}
+
+		for _, st := range problem.streets {
+			if st.L < config.Lmin || st.L > config.Lmax || st.B == st.E {
+				t.Errorf("%s: invalid street %+v", name, st)
+			}
+		}
+
+		for _, vehicle := range problem.vehicles {
+			length := 0
+			for i := 1; i < len(vehicle.path); i++ {
+				if problem.streets[vehicle.path[i-1]].E != problem.streets[vehicle.path[i]].B {
+					t.Fatalf("%s: vehicle %d drives disconnected streets", name, vehicle.id)
+				}
+				length += problem.streets[vehicle.path[i]].L
+			}
+
+			if length > config.D {
+				t.Errorf("%s: vehicle %d needs %d seconds, more than D=%d", name, vehicle.id, length, config.D)
+			}
+		}
+
+		filename := filepath.Join(t.TempDir(), "in.txt")
+		problem.WriteInput(filename)
+		parsed := Parse(filename)
+
+		// The parser does not record the IDs of streets and intersections
+		for sid := range problem.streets {
+			problem.streets[sid].id = 0
+		}
+		for iid := range problem.intersections {
+			problem.intersections[iid].id = 0
+		}
+
+		if !reflect.DeepEqual(parsed, problem) {
+			t.Errorf("%s: parsed instance differs from the generated one", name)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	valid := generatorConfigs()[0]
+
+	tests := []struct {
+		name   string
+		modify func(config *GeneratorConfig)
+	}{
+		{"one intersection", func(config *GeneratorConfig) { config.I = 1 }},
+		{"zero length streets", func(config *GeneratorConfig) { config.Lmin = 0 }},
+		{"streets longer than D", func(config *GeneratorConfig) { config.Lmax = config.D + 1 }},
+		{"too few streets", func(config *GeneratorConfig) { config.S = 3 }},
+		{"too many streets", func(config *GeneratorConfig) { config.S = config.I * config.I }},
+		{"unknown topology", func(config *GeneratorConfig) { config.Topology = "ring" }},
+		{"unknown lengths", func(config *GeneratorConfig) { config.Lengths = "normal" }},
+	}
+
+	for _, test := range tests {
+		config := valid
+		test.modify(&config)
+		if _, err := Generate(config); err == nil {
+			t.Errorf("%s: got no error", test.name)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	// 0 -> 1 -> 3 takes 2 + 2 seconds, 0 -> 2 -> 3 takes 1 + 5 and the
+	// direct street 0 -> 3 takes 7. Nothing leaves 3, and 4 is isolated.
+	problem := parseString(t, "10 5 5 0 10\n"+
+		"0 1 a 2\n1 3 b 2\n0 2 c 1\n2 3 d 5\n0 3 e 7\n")
+
+	tests := []struct {
+		from, to int
+		path     []int
+		length   int
+	}{
+		{0, 3, []int{0, 1}, 4},
+		{0, 2, []int{2}, 1},
+		{2, 3, []int{3}, 5},
+		{0, 0, []int{}, 0},
+		{3, 0, nil, 0},
+		{0, 4, nil, 0},
+	}
+
+	for _, test := range tests {
+		path, length := problem.ShortestPath(test.from, test.to)
+		if !reflect.DeepEqual(path, test.path) || length != test.length {
+			t.Errorf("%d -> %d: got %v (%d), want %v (%d)", test.from, test.to, path, length, test.path, test.length)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+func RankMap(values map[int]int) []int {
+	type kv struct {
+		Key   int
+		Value int
+	}
+
+	var ss []kv
+
+	for k, v := range values {
+		ss = append(ss, kv{k, v})
+	}
+
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Value > ss[j].Value
+	})
+
+	ranked := make([]int, len(values))
+	for i, kv := range ss {
+		ranked[i] = kv.Key
+	}
+
+	return ranked
+}
+
+// Improves the solution for `maxtime` seconds, or until `ctx` is cancelled,
+// alternating greedy improvements, offset shifts and random shuffles.
+func (problem Problem) ImproveRandom(ctx context.Context, solution Solution, maxtime float64) Solution {
+	score, _ := problem.Simulate(solution)
+	snapshot := func() []byte { return problem.Format(solution) }
+
+	max := int(problem.S/200 + 1) // In one iteration we improve top 2% jammed streets
+	fmt.Println("[*] Improving at most", max, "jams at once")
+
+	start := time.Now()
+
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		var iscore int
+		if r := rand.Intn(100); r < 55 {
+			// 55% of the times, try to improve
+			fmt.Println("[*] Perform greedy improvements")
+			solution = problem.Improve(ctx, solution, maxtime/50+1)
+			fmt.Println("[*] Greedy improvements completed")
+			iscore, _ = problem.Simulate(solution)
+		} else if r < 70 {
+			// 15% of the times, shift the offsets of some cycles
+			fmt.Println("[*] Perform offset improvements")
+			solution = problem.ImproveOffsets(ctx, solution, maxtime/50+1)
+			fmt.Println("[*] Offset improvements completed")
+			iscore, _ = problem.Simulate(solution)
+		} else {
+			// 30% of the times, randomize
+			fmt.Println("[*] Randomizing schedules")
+
+			backupiids := make(chan int, max)
+			backupstreets := make(chan []int, max)
+			backuptgreens := make(chan []int, max)
+
+			for __ := 0; __ < rand.Intn(max); __++ {
+				// This for-loop is just a trick to get a random entry
+				for iid, schedule := range solution {
+					copystreets := make([]int, len(solution[iid].streets))
+					copytgreens := make([]int, len(solution[iid].tgreens))
+					copy(copystreets, solution[iid].streets)
+					copy(copytgreens, solution[iid].tgreens)
+					backupiids <- iid
+					backupstreets <- copystreets
+					backuptgreens <- copytgreens
+					for i := 0; i < int(len(schedule.streets)/2); i++ {
+						j := rand.Intn(len(schedule.streets))
+						solution[iid].streets[i], solution[iid].streets[j] = solution[iid].streets[j], solution[iid].streets[i]
+						solution[iid].tgreens[i], solution[iid].tgreens[j] = solution[iid].tgreens[j], solution[iid].tgreens[i]
+					}
+
+					break
+				}
+			}
+			iscore, _ = problem.Simulate(solution)
+			problem.tracker.Move(iscore, snapshot)
+			fmt.Println("[*] Randomization completed:", iscore)
+			if iscore < score {
+				fmt.Println("[*] Unlucky randomization. Restoring...")
+				for len(backupiids) > 0 {
+					iid := <-backupiids
+					copy(solution[iid].streets, <-backupstreets)
+					copy(solution[iid].tgreens, <-backuptgreens)
+				}
+			}
+		}
+
+		if iscore > score {
+			fmt.Printf(
+				"[*] Improvement (%d): %d\n",
+				max,
+				iscore,
+			)
+			score = iscore
+		}
+	}
+
+	return solution
+}
+
+func (problem Problem) ImproveJams(ctx context.Context, solution Solution, maxtime float64) Solution {
+	score, stats := problem.Simulate(solution)
+	snapshot := func() []byte { return problem.Format(solution) }
+
+	max := int(problem.S/200 + 1) // In one iteration we improve top 2% jammed streets
+	fmt.Println("[*] Improving at most", max, "jams at once")
+
+	start := time.Now()
+
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		if max <= 2 {
+			max = int(problem.S/200 + 1)
+			if rand.Intn(100) < 70 {
+				// 70% of the times, try to improve
+				fmt.Println("[*] Perform greedy improvements")
+				solution = problem.Improve(ctx, solution, maxtime/20)
+				score, stats = problem.Simulate(solution)
+				fmt.Println("[*] Greedy improvements completed")
+			} else {
+				// 30% of the times, randomize
+				fmt.Println("[*] Randomizing schedules")
+				for __ := 0; __ < rand.Intn(max); __++ {
+					// This for-loop is just a trick to get a random entry
+					for iid, schedule := range solution {
+						for i := 0; i < int(len(schedule.streets)/2); i++ {
+							j := rand.Intn(len(schedule.streets))
+							solution[iid].streets[i], solution[iid].streets[j] = solution[iid].streets[j], solution[iid].streets[i]
+							solution[iid].tgreens[i], solution[iid].tgreens[j] = solution[iid].tgreens[j], solution[iid].tgreens[i]
+						}
+
+						break
+					}
+				}
+				score, stats = problem.Simulate(solution)
+				problem.tracker.Move(score, snapshot)
+				fmt.Println("[*] Randomization completed:", score)
+			}
+		}
+		topjammed := RankMap(stats.jampeaks)
+
+		for i := 0; i < max; i++ {
+			sid := topjammed[i]
+			iid := problem.streets[sid].E
+			if schedule, found := solution[iid]; found && schedule.Duration() < problem.D {
+				for k, s := range schedule.streets {
+					if s == sid {
+						solution[iid].tgreens[k]++
+					}
+					break
+				}
+			} else {
+				topjammed[i] = -1
+			}
+		}
+
+		iscore, istats := problem.Simulate(solution)
+		problem.tracker.Move(iscore, snapshot)
+		if iscore > score {
+			fmt.Printf(
+				"[*] Improvement (%d): %d\n",
+				max,
+				iscore,
+			)
+			score = iscore
+			stats = istats
+		} else {
+			// Revert and lower max
+			for i := 0; i < max; i++ {
+				if sid := topjammed[i]; sid != -1 {
+					iid := problem.streets[sid].E
+					for k, s := range solution[iid].streets {
+						if s == sid {
+							solution[iid].tgreens[k]--
+						}
+						break
+					}
+				}
+			}
+
+			max = int(max/2) + 1
+		}
+	}
+
+	return solution
+}
+
+func (problem Problem) Improve(ctx context.Context, solution Solution, maxtime float64) Solution {
+	score, _ := problem.Simulate(solution)
+	snapshot := func() []byte { return problem.Format(solution) }
+
+	start := time.Now()
+
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		anyimprovement := false
+		for iid := range solution {
+			if solution[iid].Duration() >= problem.D {
+				continue
+			}
+
+			for k := range solution[iid].tgreens {
+				solution[iid].tgreens[k]++
+				iscore, _ := problem.Simulate(solution)
+				problem.tracker.Move(iscore, snapshot)
+				for iscore > score {
+					anyimprovement = true
+					score = iscore
+					fmt.Printf(
+						"[*] Improvement (iid %d, street %d, tgreen %d): %d\n",
+						iid,
+						solution[iid].streets[k],
+						solution[iid].tgreens[k],
+						iscore,
+					)
+					solution[iid].tgreens[k]++
+					iscore, _ = problem.Simulate(solution)
+					problem.tracker.Move(iscore, snapshot)
+					if solution[iid].Duration() >= problem.D {
+						break
+					}
+
+					if ctx.Err() != nil || time.Now().Sub(start).Seconds() > maxtime {
+						break
+					}
+				}
+				solution[iid].tgreens[k]--
+
+				if ctx.Err() != nil || time.Now().Sub(start).Seconds() > maxtime {
+					break
+				}
+			}
+
+			if ctx.Err() != nil || time.Now().Sub(start).Seconds() > maxtime {
+				break
+			}
+		}
+
+		if !anyimprovement {
+			break
+		}
+	}
+
+	return solution
+}
+
+// Rotates the schedules of random intersections, keeping a rotation only if it
+// improves the score. Unlike the randomization in ImproveRandom, a rotation
+// preserves the cycle of the intersection and only shifts its phase.
+func (problem Problem) ImproveOffsets(ctx context.Context, solution Solution, maxtime float64) Solution {
+	score, _ := problem.Simulate(solution)
+	snapshot := func() []byte { return problem.Format(solution) }
+
+	// Only intersections with at least two semaphores can be rotated
+	iids := make([]int, 0)
+	for iid, schedule := range solution {
+		if len(schedule.streets) > 1 {
+			iids = append(iids, iid)
+		}
+	}
+
+	if len(iids) == 0 {
+		return solution
+	}
+
+	start := time.Now()
+
+	for ctx.Err() == nil && time.Now().Sub(start).Seconds() < maxtime {
+		iid := iids[rand.Intn(len(iids))]
+		schedule := solution[iid]
+		k := 1 + rand.Intn(len(schedule.streets)-1)
+
+		solution[iid] = schedule.Rotate(k)
+		iscore, _ := problem.Simulate(solution)
+		problem.tracker.Move(iscore, snapshot)
+		if iscore > score {
+			fmt.Printf(
+				"[*] Improvement (iid %d, rotation %d, offset %d): %d\n",
+				iid,
+				k,
+				schedule.Offset(k),
+				iscore,
+			)
+			score = iscore
+		} else {
+			solution[iid] = schedule
+		}
+	}
+
+	return solution
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/mcieno/HashCode/internal/dashboard"
+	"github.com/mcieno/HashCode/internal/runner"
+)
+
+// The datasets solve and improve process.
+func datasets() []runner.Job {
+	return []runner.Job{
+		//{Dataset: "a", Title: "A – An example", Input: "in/a.txt", Output: "out/a.txt", Start: "out/a.txt"},
+		{Dataset: "b", Title: "B – By the ocean", Input: "in/b.txt", Output: "out/b.txt", Start: "out/b.txt"},
+		{Dataset: "c", Title: "C – Checkmate", Input: "in/c.txt", Output: "out/c.txt", Start: "out/c.txt"},
+		{Dataset: "d", Title: "D – Daily commute", Input: "in/d.txt", Output: "out/d.txt", Start: "out/d.txt"},
+		{Dataset: "e", Title: "E – Etoile", Input: "in/e.txt", Output: "out/e.txt", Start: "out/e.txt"},
+		{Dataset: "f", Title: "F – Forever jammed", Input: "in/f.txt", Output: "out/f.txt", Start: "out/f.txt"},
+	}
+}
+
+// The method Solve uses for each dataset.
+var methods = map[string]int{
+	"a": MethodA,
+	"b": MethodB,
+	"c": MethodC,
+	"d": MethodD,
+	"e": MethodE,
+	"f": MethodF,
+}
+
+// Solves every dataset from scratch with `pool` and improves the result for the
+// time budget of the dataset, publishing the progress to `progress`. With
+// `greenwave`, every dataset starts from MethodGreenWave instead of its own
+// method. Interrupted improvements still export what they have found.
+func solveMain(ctx context.Context, pool runner.Runner, jobs []runner.Job, greenwave bool, progress *dashboard.Progress) {
+	results := pool.Run(ctx, jobs, func(ctx context.Context, job runner.Job) (int, error) {
+		fmt.Println("[+] Solving problem", job.Dataset)
+		problem := Parse(job.Input)
+		fmt.Println(
+			job.Dataset,
+			"[*] Problem parsed from file",
+			problem.D,
+			problem.F,
+			problem.I,
+			problem.S,
+			problem.V,
+		)
+
+		method := methods[job.Dataset]
+		if greenwave {
+			method = MethodGreenWave
+		}
+
+		fmt.Println(job.Dataset, "[*] Solving...")
+		solution := problem.Solve(method)
+
+		fmt.Println(job.Dataset, "[*] Simulating solution...")
+		score, _ := problem.Simulate(solution)
+		fmt.Println(job.Dataset, "[*] First solution has score", score)
+
+		problem.tracker = progress.Track(job.Dataset, score, func() []byte { return problem.Format(solution) })
+		isolution := problem.ImproveRandom(ctx, solution, runner.Seconds(ctx))
+		iscore, _ := problem.Simulate(isolution)
+		problem.tracker.Done(iscore, func() []byte { return problem.Format(isolution) })
+		fmt.Println(job.Dataset, "[*] Final solution has score", iscore)
+
+		fname := fmt.Sprintf("%s%d", job.Output, iscore)
+		problem.Export(isolution, fname)
+		fmt.Println(job.Dataset, "[*] Solution written to", fname)
+
+		return iscore, nil
+	})
+
+	fmt.Print(runner.Summary(results))
+}
+
+// Imports the current solution of every dataset and further improves it for
+// the time budget of the dataset, publishing the progress to `progress`.
+// Interrupted improvements still export what they have found.
+func improveMain(ctx context.Context, pool runner.Runner, jobs []runner.Job, progress *dashboard.Progress) {
+	results := pool.Run(ctx, jobs, func(ctx context.Context, job runner.Job) (int, error) {
+		problem := Parse(job.Input)
+		fmt.Println(
+			job.Dataset,
+			"[*] Problem parsed from file:",
+			problem.D,
+			problem.F,
+			problem.I,
+			problem.S,
+			problem.V,
+		)
+
+		fmt.Println(job.Dataset, "[*] Importing...")
+		solution := problem.Import(job.Start)
+		score, _ := problem.Simulate(solution)
+		fmt.Println(
+			job.Dataset,
+			"[*] Solution imported - score:",
+			score,
+		)
+
+		problem.tracker = progress.Track(job.Dataset, score, func() []byte { return problem.Format(solution) })
+		isolution := problem.ImproveRandom(ctx, solution, runner.Seconds(ctx))
+		iscore, _ := problem.Simulate(isolution)
+		problem.tracker.Done(iscore, func() []byte { return problem.Format(isolution) })
+		fmt.Println(job.Dataset, "[*] Final solution has score", iscore)
+
+		fname := fmt.Sprintf("%s%d", job.Output, iscore)
+		problem.Export(isolution, fname)
+		fmt.Println(job.Dataset, "[*] Solution written to", fname)
+
+		return iscore, nil
+	})
+
+	fmt.Print(runner.Summary(results))
+}
+
+// Writes a random instance of the problem configured by command line flags.
+func generateMain(args []string) {
+	cmd := flag.NewFlagSet("generate", flag.ExitOnError)
+	config := GeneratorConfig{}
+	cmd.IntVar(&config.D, "D", 100, "duration of the simulation")
+	cmd.IntVar(&config.I, "I", 16, "number of intersections")
+	cmd.IntVar(&config.S, "S", 48, "number of streets")
+	cmd.IntVar(&config.V, "V", 20, "number of vehicles")
+	cmd.IntVar(&config.F, "F", 100, "bonus points for reaching destination")
+	cmd.IntVar(&config.Lmin, "lmin", 1, "minimum length of a street")
+	cmd.IntVar(&config.Lmax, "lmax", 10, "maximum length of a street")
+	cmd.StringVar(&config.Lengths, "lengths", LengthsUniform, "street length distribution: uniform or exponential")
+	cmd.StringVar(&config.Topology, "topology", TopologyGrid, "city topology: grid, radial or random")
+	cmd.Int64Var(&config.Seed, "seed", 1, "seed of the random number generator")
+	output := cmd.String("o", "", "file the instance is written to")
+	cmd.Parse(args)
+
+	if *output == "" {
+		fmt.Fprintln(os.Stderr, "generate: missing -o")
+		cmd.Usage()
+		os.Exit(2)
+	}
+
+	problem, err := Generate(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "generate:", err)
+		os.Exit(1)
+	}
+
+	problem.WriteInput(*output)
+	fmt.Println("[*] Instance written to", *output)
+}
+
+// Merges the solutions given as arguments, picking for each intersection the
+// schedule that works best with the others. An interrupt stops the merge, still
+// writing what has been merged.
+func mergeMain(args []string) {
+	cmd := flag.NewFlagSet("merge", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem the solutions are for")
+	output := cmd.String("o", "", "prefix of the file the merged solution is written to (its score is appended)")
+	maxtime := cmd.Float64("maxtime", 3600, "seconds spent merging")
+	cmd.Parse(args)
+
+	if *input == "" || *output == "" || cmd.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: go run . merge -in <problem> -o <output> <solution>...")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	problem := Parse(*input)
+
+	solutions := make([]Solution, cmd.NArg())
+	for i, filename := range cmd.Args() {
+		solutions[i] = problem.Import(filename)
+		score, _ := problem.Simulate(solutions[i])
+		fmt.Println("[*] Solution", i, "imported from", filename, "- score:", score)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(*maxtime*float64(time.Second)))
+	defer cancel()
+
+	merged, score, origins := problem.Merge(ctx, solutions)
+
+	taken := make([]int, len(solutions))
+	for iid := 0; iid < problem.I; iid++ {
+		if i, found := origins[iid]; found {
+			taken[i]++
+			fmt.Println("[*] Intersection", iid, "from", cmd.Arg(i))
+		}
+	}
+
+	for i, filename := range cmd.Args() {
+		fmt.Println("[*]", taken[i], "intersections from", filename)
+	}
+
+	fname := fmt.Sprintf("%s%d", *output, score)
+	problem.Export(merged, fname)
+	fmt.Println("[*] Merged solution with score", score, "written to", fname)
+}
+
+// Returns where optimisers publish their progress, serving it on `addr` unless
+// empty. The optimisations run anyway if the dashboard cannot be served.
+func newProgress(addr string) *dashboard.Progress {
+	progress := dashboard.NewProgress()
+	if addr != "" {
+		if err := dashboard.Serve(addr, "Traffic Signaling", progress); err != nil {
+			fmt.Fprintln(os.Stderr, "[!] Dashboard unavailable:", err)
+		}
+	}
+
+	return progress
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: go run . <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  solve    solve every dataset from scratch")
+	fmt.Fprintln(os.Stderr, "  improve  further improve the solutions in out/")
+	fmt.Fprintln(os.Stderr, "  generate write a random instance of the problem")
+	fmt.Fprintln(os.Stderr, "  merge    merge several solutions of the same problem")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	maxtime := cmd.Float64("maxtime", 3600, "seconds spent improving each dataset")
+	budgets := cmd.String("budgets", "", "seconds spent on specific datasets, e.g. c=600,e=120")
+	workers := cmd.Int("workers", runtime.NumCPU(), "datasets processed at once")
+	greenwave := cmd.Bool("greenwave", false, "align the offsets of the schedules solve starts from")
+	addr := cmd.String("http", "", "serve a live progress dashboard on this address (e.g. :8080)")
+
+	// Stops at the first interrupt, still exporting what has been found, and
+	// dies at the second one
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Returns the runner and the datasets configured by the flags above
+	run := func() (runner.Runner, []runner.Job) {
+		cmd.Parse(os.Args[2:])
+
+		perdataset, err := runner.ParseBudgets(*budgets)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		jobs := datasets()
+		for k := range jobs {
+			jobs[k].Budget = perdataset[jobs[k].Dataset]
+		}
+
+		return runner.Runner{Workers: *workers, Budget: time.Duration(*maxtime * float64(time.Second))}, jobs
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		generateMain(os.Args[2:])
+	case "merge":
+		mergeMain(os.Args[2:])
+	case "solve":
+		pool, jobs := run()
+		solveMain(ctx, pool, jobs, *greenwave, newProgress(*addr))
+	case "improve":
+		pool, jobs := run()
+		improveMain(ctx, pool, jobs, newProgress(*addr))
+	default:
+		usage()
+	}
+}
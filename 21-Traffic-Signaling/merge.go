@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Merges several solutions into one that is at least as good as the best of
+// them. Starting from the best solution, the schedule of each intersection is
+// replaced by the one of another solution (or dropped, if another solution has
+// none) whenever that improves the score, until a whole pass brings no
+// improvement or `ctx` is done.
+// Returns the merged solution, its score and, for each scheduled intersection,
+// the index of the solution its schedule was taken from.
+func (problem Problem) Merge(ctx context.Context, solutions []Solution) (Solution, int, map[int]int) {
+	if len(solutions) == 0 {
+		panic("Nothing to merge")
+	}
+
+	best, score := 0, -1
+	for i, solution := range solutions {
+		if iscore, _ := problem.Simulate(solution); iscore > score {
+			best, score = i, iscore
+		}
+	}
+
+	merged := make(Solution)
+	origins := make(map[int]int)
+	for iid, schedule := range solutions[best] {
+		merged[iid] = schedule.Clone()
+		origins[iid] = best
+	}
+
+	// Only intersections on which the solutions disagree are worth a try
+	iids := make([]int, 0)
+	for iid := 0; iid < problem.I; iid++ {
+		schedule, found := solutions[best][iid]
+		for _, solution := range solutions {
+			if other, ofound := solution[iid]; ofound != found || (found && !schedule.Equal(other)) {
+				iids = append(iids, iid)
+				break
+			}
+		}
+	}
+	sort.Ints(iids)
+
+	fmt.Println("[*] Merging", len(iids), "intersections starting from solution", best, "with score", score)
+
+	for ctx.Err() == nil {
+		anyimprovement := false
+		for _, iid := range iids {
+			for i, solution := range solutions {
+				current, found := merged[iid]
+				candidate, cfound := solution[iid]
+				if cfound == found && (!found || candidate.Equal(current)) {
+					continue
+				}
+
+				if cfound {
+					merged[iid] = candidate.Clone()
+				} else {
+					delete(merged, iid)
+				}
+
+				if iscore, _ := problem.Simulate(merged); iscore > score {
+					fmt.Printf(
+						"[*] Improvement (iid %d from solution %d): %d\n",
+						iid,
+						i,
+						iscore,
+					)
+					anyimprovement = true
+					score = iscore
+					if cfound {
+						origins[iid] = i
+					} else {
+						delete(origins, iid)
+					}
+				} else if found {
+					merged[iid] = current
+				} else {
+					delete(merged, iid)
+				}
+
+				if ctx.Err() != nil {
+					break
+				}
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		if !anyimprovement {
+			break
+		}
+	}
+
+	return merged, score, origins
+}
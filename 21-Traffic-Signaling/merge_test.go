@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	// Two independent intersections: vehicles drive "a" then "b" through
+	// intersection 1 and "e" then "f" through intersection 3.
+	problem := parseString(t, "6 6 6 2 10\n"+
+		"0 1 a 1\n2 1 c 1\n1 2 b 3\n"+
+		"5 3 e 1\n4 3 g 1\n3 4 f 3\n"+
+		"2 a b\n2 e f\n")
+
+	solutions := []Solution{
+		{
+			1: {id: 1, streets: []int{0}, tgreens: []int{1}},
+			3: {id: 3, streets: []int{4}, tgreens: []int{1}},
+		},
+		{
+			1: {id: 1, streets: []int{1}, tgreens: []int{1}},
+			3: {id: 3, streets: []int{3}, tgreens: []int{1}},
+		},
+	}
+
+	for i, solution := range solutions {
+		if score, _ := problem.Simulate(solution); score != 13 {
+			t.Fatalf("got score %d for solution %d, want %d", score, i, 13)
+		}
+	}
+
+	merged, score, origins := problem.Merge(context.Background(), solutions)
+	if score != 26 {
+		t.Errorf("got merged score %d, want %d", score, 26)
+	}
+
+	if simulated, _ := problem.Simulate(merged); simulated != score {
+		t.Errorf("got simulated score %d, reported %d", simulated, score)
+	}
+
+	if o, ok := origins[1]; !ok || o != 0 {
+		t.Errorf("got origins %v, want intersection 1 from solution 0", origins)
+	}
+	if o, ok := origins[3]; !ok || o != 1 {
+		t.Errorf("got origins %v, want intersection 3 from solution 1", origins)
+	}
+}
+
+func TestMergeNeverWorse(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solutions := []Solution{problem.TrivialSolve(), problem.Solve(MethodA)}
+
+	best := 0
+	for _, solution := range solutions {
+		if score, _ := problem.Simulate(solution); score > best {
+			best = score
+		}
+	}
+
+	if _, score, _ := problem.Merge(context.Background(), solutions); score < best {
+		t.Errorf("got merged score %d, want at least %d", score, best)
+	}
+}
+
+// Once cancelled, the merge must return the best of the solutions as it is.
+func TestMergeCancelled(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solutions := []Solution{problem.TrivialSolve(), problem.Solve(MethodA)}
+	want, _ := problem.Simulate(solutions[1])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, score, _ := problem.Merge(ctx, solutions); score != want {
+		t.Errorf("got merged score %d, want %d", score, want)
+	}
+}
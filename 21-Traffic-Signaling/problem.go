@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/mcieno/HashCode/internal/dashboard"
+)
+
+const (
+	MethodA = iota
+	MethodB = iota
+	MethodC = iota
+	MethodD = iota
+	MethodE = iota
+	MethodF = iota
+
+	// Trivial schedules with their offsets aligned by GreenWave
+	MethodGreenWave = iota
+)
+
+// A Street, described by the intersection it originates from (B), the
+// intersection it arrives to (E), a length (L) and a unique name.
+type Street struct {
+	id   int    // ID of the street itself
+	B    int    // ID of the intersection where the street begins
+	E    int    // ID of the intersection where the street ends
+	L    int    // Time required to get from B to E
+	name string // name of the street (used when printing the solution)
+}
+
+// An Intersection, described by the set of incoming and the set of outgoing
+// streets.
+type Intersection struct {
+	id       int   // ID of the intersection itself
+	incoming []int // Set of street IDs that end in this intersection
+	outgoing []int // Set of street IDs that begin in this intersection
+}
+
+// A Vehicle, described by the path the car has to drive.
+type Vehicle struct {
+	id   int   // ID of the vehicle itself.
+	path []int // List of street ids the vehicle must travel.
+}
+
+// A convenient Problem object to pass around
+type Problem struct {
+	D             int                // Duration of the simulation
+	I             int                // Number of Intersections
+	S             int                // Number of Streets
+	V             int                // Number of Vehicles
+	F             int                // Bonus points for reaching destination
+	streets       []Street           // The main data structure representing the problem input is a collection of streets (by ID)
+	streetids     map[string]int     // A map from names to IDs to do reverse lookups
+	intersections []Intersection     // All intersections of the map
+	vehicles      []Vehicle          // All vehicles in the simulation
+	tracker       *dashboard.Tracker // Where optimisers publish their progress, if anywhere
+}
+
+func Parse(filename string) Problem {
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	lines := strings.Split(string(input), "\n")
+	header, dataset := lines[0], lines[1:]
+
+	var D, I, S, V, F int
+	if _, err := fmt.Sscanf(header, "%d %d %d %d %d", &D, &I, &S, &V, &F); err != nil {
+		panic(err)
+	}
+
+	streetsData, vehiclesData := dataset[:S], dataset[S:]
+
+	streets := make([]Street, S)
+	streetids := make(map[string]int)
+
+	intersections := make([]Intersection, I)
+	for i := range intersections {
+		intersections[i] = Intersection{
+			incoming: make([]int, 0),
+			outgoing: make([]int, 0),
+		}
+	}
+
+	for k, streetdata := range streetsData {
+		var b, e, l int
+		var name string
+
+		if _, err := fmt.Sscanf(streetdata, "%d %d %s %d", &b, &e, &name, &l); err != nil {
+			panic(err)
+		}
+
+		st := Street{B: b, E: e, L: l, name: name}
+
+		streets[k] = st
+		streetids[name] = k
+		intersections[st.E].incoming = append(intersections[st.E].incoming, k)
+		intersections[st.B].outgoing = append(intersections[st.B].outgoing, k)
+	}
+
+	vehicles := make([]Vehicle, V)
+
+	for i := range vehicles {
+		pathspec := strings.Split(vehiclesData[i], " ")
+		nstreetsstr, streetnames := pathspec[0], pathspec[1:]
+
+		var nstreets int
+		if _, err := fmt.Sscanf(nstreetsstr, "%d", &nstreets); err != nil {
+			panic(err)
+		}
+
+		path := make([]int, nstreets)
+		for k, name := range streetnames {
+			path[k] = streetids[name]
+		}
+
+		vehicles[i] = Vehicle{id: i, path: path}
+	}
+
+	for _, vehicle := range vehicles {
+		for i, p := range vehicle.path {
+			for _, pp := range vehicle.path[i+1:] {
+				if p == pp {
+					panic("Same street in path")
+				}
+			}
+		}
+	}
+
+	return Problem{
+		D:             D,
+		I:             I,
+		S:             S,
+		V:             V,
+		F:             F,
+		streets:       streets,
+		streetids:     streetids,
+		intersections: intersections,
+		vehicles:      vehicles,
+	}
+}
+
+func (problem *Problem) Validate(solution Solution) {
+	if len(solution) > problem.I {
+		fmt.Println(len(solution), problem.I)
+		panic("Too many schedules in solution")
+	}
+
+	for iid, schedule := range solution {
+		if iid != schedule.id {
+			fmt.Println(iid, schedule.id)
+			panic("Intersection ID mismatch")
+		}
+
+		if iid < 0 || iid >= problem.I {
+			fmt.Println(iid, problem.I)
+			panic("Invalid intersection ID")
+		}
+
+		if len(schedule.streets) != len(schedule.tgreens) {
+			fmt.Println(len(schedule.streets), len(schedule.tgreens))
+			panic("Different lengths of schedule.streets and schedule.tgreens")
+		}
+
+		if len(schedule.streets) == 0 {
+			panic("Empty schedule")
+		}
+
+		if len(schedule.streets) > len(problem.intersections[iid].incoming) {
+			fmt.Println(schedule.streets, problem.intersections[iid].incoming)
+			panic("Too many streets in schedule")
+		}
+
+		for _, sid := range schedule.streets {
+			if problem.streets[sid].E != iid {
+				fmt.Println(iid, problem.streets[sid])
+				panic("Schedule for semaphore not in this intersection")
+			}
+		}
+
+		tottime := 0
+		for _, tgreen := range schedule.tgreens {
+			if tgreen < 1 {
+				panic("Bad time for green light")
+			}
+
+			tottime += tgreen
+
+			if tottime > problem.D {
+				panic("Too long schedule")
+			}
+		}
+	}
+}
+
+// Returns true if at least one vehicle has the street in its path.
+func (problem Problem) IsStreetUsed(streetid int) bool {
+	for _, vehicle := range problem.vehicles {
+		for _, sid := range vehicle.path {
+			if streetid == sid {
+				return true
+			}
+		}
+	}
+
+	return false
+}
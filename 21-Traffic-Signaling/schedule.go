@@ -0,0 +1,128 @@
+package main
+
+// The Schedule of an intersection is described by the ID of the intersection
+// and the array of semaphores of the streets belonging to that intersection.
+type Schedule struct {
+	id      int   // ID of the intersection this schedule is for
+	streets []int // List of street IDs in this intersection whose semaphores are part of the schedule
+	tgreens []int // List of times each of the corresponding street semaphore remains green
+}
+
+// A solution is a map from intersection IDs to its schedule.
+type Solution map[int]Schedule
+
+// Returns the duration of a schedule.
+func (schedule Schedule) Duration() int {
+	acc := 0
+	for _, t := range schedule.tgreens {
+		acc += t
+	}
+	return acc
+}
+
+// Returns the ID of the semaphore that is green at a certain moment.
+func (schedule Schedule) WhichGreen(when int) int {
+	when %= schedule.Duration()
+
+	acc := 0
+	for i, t := range schedule.tgreens {
+		acc += t
+		if acc > when {
+			return schedule.streets[i]
+		}
+	}
+
+	panic("Unknown error")
+}
+
+// Returns the time at which the k-th semaphore of the schedule turns green
+// within a cycle.
+func (schedule Schedule) Offset(k int) int {
+	acc := 0
+	for _, t := range schedule.tgreens[:k] {
+		acc += t
+	}
+	return acc
+}
+
+// Returns the time at which the semaphore of street `sid` turns green within a
+// cycle and for how long it stays green. Returns -1, 0 if the street is not
+// part of the schedule.
+func (schedule Schedule) GreenWindow(sid int) (int, int) {
+	acc := 0
+	for i, t := range schedule.tgreens {
+		if schedule.streets[i] == sid {
+			return acc, t
+		}
+		acc += t
+	}
+
+	return -1, 0
+}
+
+// Returns a copy of the schedule rotated by `k` phases, so that the semaphore
+// that used to be the k-th is green at t=0. The order of the semaphores and
+// their green times are preserved, hence the cycle is unchanged and only its
+// offset shifts by Offset(k).
+func (schedule Schedule) Rotate(k int) Schedule {
+	n := len(schedule.streets)
+	k = ((k % n) + n) % n
+
+	rotated := Schedule{
+		id:      schedule.id,
+		streets: make([]int, 0, n),
+		tgreens: make([]int, 0, n),
+	}
+	rotated.streets = append(rotated.streets, schedule.streets[k:]...)
+	rotated.streets = append(rotated.streets, schedule.streets[:k]...)
+	rotated.tgreens = append(rotated.tgreens, schedule.tgreens[k:]...)
+	rotated.tgreens = append(rotated.tgreens, schedule.tgreens[:k]...)
+
+	return rotated
+}
+
+// Returns the number of seconds a vehicle arriving at the semaphore of street
+// `sid` at time `when` waits before the light turns green, ignoring any queue.
+func (schedule Schedule) Wait(sid, when int) int {
+	start, length := schedule.GreenWindow(sid)
+	if start == -1 {
+		panic("Street not in schedule")
+	}
+
+	duration := schedule.Duration()
+	delta := ((when-start)%duration + duration) % duration
+	if delta < length {
+		return 0
+	}
+
+	return duration - delta
+}
+
+// Returns a deep copy of the schedule.
+func (schedule Schedule) Clone() Schedule {
+	clone := Schedule{
+		id:      schedule.id,
+		streets: make([]int, len(schedule.streets)),
+		tgreens: make([]int, len(schedule.tgreens)),
+	}
+	copy(clone.streets, schedule.streets)
+	copy(clone.tgreens, schedule.tgreens)
+
+	return clone
+}
+
+// Returns true if both schedules turn the same semaphores green in the same
+// order and for the same times.
+func (schedule Schedule) Equal(other Schedule) bool {
+	if schedule.id != other.id || len(schedule.streets) != len(other.streets) {
+		return false
+	}
+
+	for i := range schedule.streets {
+		if schedule.streets[i] != other.streets[i] || schedule.tgreens[i] != other.tgreens[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Returns true if `other` is `schedule` rotated by some number of phases.
+func isRotation(schedule, other Schedule) bool {
+	for k := range schedule.streets {
+		if schedule.Rotate(k).Equal(other) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestRotate(t *testing.T) {
+	schedule := Schedule{id: 7, streets: []int{10, 20, 30}, tgreens: []int{2, 3, 1}}
+
+	tests := []struct {
+		k       int
+		streets []int
+		tgreens []int
+	}{
+		{0, []int{10, 20, 30}, []int{2, 3, 1}},
+		{1, []int{20, 30, 10}, []int{3, 1, 2}},
+		{2, []int{30, 10, 20}, []int{1, 2, 3}},
+		{3, []int{10, 20, 30}, []int{2, 3, 1}},
+		{4, []int{20, 30, 10}, []int{3, 1, 2}},
+		{-1, []int{30, 10, 20}, []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		rotated := schedule.Rotate(test.k)
+		want := Schedule{id: 7, streets: test.streets, tgreens: test.tgreens}
+		if !reflect.DeepEqual(rotated, want) {
+			t.Errorf("Rotate(%d): got %+v, want %+v", test.k, rotated, want)
+		}
+
+		if rotated.Duration() != schedule.Duration() {
+			t.Errorf("Rotate(%d): got duration %d, want %d", test.k, rotated.Duration(), schedule.Duration())
+		}
+	}
+
+	// The original schedule must be left untouched
+	if want := []int{10, 20, 30}; !reflect.DeepEqual(schedule.streets, want) {
+		t.Errorf("got streets %v after rotating, want %v", schedule.streets, want)
+	}
+}
+
+func TestOffsetAndGreenWindow(t *testing.T) {
+	schedule := Schedule{id: 7, streets: []int{10, 20, 30}, tgreens: []int{2, 3, 1}}
+
+	tests := []struct {
+		k, sid         int
+		offset, length int
+	}{
+		{0, 10, 0, 2},
+		{1, 20, 2, 3},
+		{2, 30, 5, 1},
+	}
+
+	for _, test := range tests {
+		if offset := schedule.Offset(test.k); offset != test.offset {
+			t.Errorf("Offset(%d): got %d, want %d", test.k, offset, test.offset)
+		}
+
+		if start, length := schedule.GreenWindow(test.sid); start != test.offset || length != test.length {
+			t.Errorf("GreenWindow(%d): got %d, %d, want %d, %d", test.sid, start, length, test.offset, test.length)
+		}
+
+		// Rotating by k phases brings the k-th semaphore to t=0
+		if start, _ := schedule.Rotate(test.k).GreenWindow(test.sid); start != 0 {
+			t.Errorf("Rotate(%d).GreenWindow(%d): got start %d, want 0", test.k, test.sid, start)
+		}
+	}
+
+	if start, length := schedule.GreenWindow(40); start != -1 || length != 0 {
+		t.Errorf("GreenWindow of a missing street: got %d, %d, want -1, 0", start, length)
+	}
+}
+
+func TestWait(t *testing.T) {
+	// Street 10 is green in [0, 2), 20 in [2, 5) and 30 in [5, 6), every
+	// 6 seconds
+	schedule := Schedule{id: 7, streets: []int{10, 20, 30}, tgreens: []int{2, 3, 1}}
+
+	tests := []struct {
+		sid, when, wait int
+	}{
+		{10, 0, 0},
+		{10, 1, 0},
+		{10, 2, 4},
+		{10, 5, 1},
+		{10, 6, 0},
+		{10, 8, 4},
+		{20, 1, 1},
+		{20, 2, 0},
+		{20, 4, 0},
+		{20, 5, 3},
+		{20, 7, 1},
+		{30, 4, 1},
+		{30, 5, 0},
+		{30, 6, 5},
+		{30, 11, 0},
+	}
+
+	for _, test := range tests {
+		if wait := schedule.Wait(test.sid, test.when); wait != test.wait {
+			t.Errorf("Wait(%d, %d): got %d, want %d", test.sid, test.when, wait, test.wait)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+// Represents a vehicle arriving to an intersection at a certain time
+type Arrival struct {
+	t   int // Time the vehicle will arrive
+	vid int // ID of the vehicle
+	pid int // Index in the vehicle path of the street this vehicle will arrive at
+}
+
+// A map from time to the list of arrivals at that time.
+type Simulation map[int][]Arrival
+
+// Simulation statistics
+type SimulationStatistics struct {
+	jampeaks map[int]int // Map from street id to the maximum number of vehicles simultaneously queued at its semaphore during the simulation.
+}
+
+// A map from street id to the queue of vehicle IDs waiting at the semaphore.
+type SemaphoreQueues map[int](chan int)
+
+// Registers the fact that vehicle with ID `vid` will arrive at the semaphore of
+// street whose index in its whole path is `pid` at time `t`.
+func (simulation Simulation) RegisterArrival(t int, arrival Arrival) {
+	if _, found := simulation[t]; !found {
+		simulation[t] = make([]Arrival, 0)
+	}
+
+	simulation[t] = append(simulation[t], arrival)
+}
+
+// Get arrivals at time `t`
+func (simulation Simulation) GetArrivals(t int) []Arrival {
+	if _, found := simulation[t]; !found {
+		return make([]Arrival, 0)
+	}
+
+	return simulation[t]
+}
+
+// Add a vehicle to the queue of the semaphore at the end of street whose ID is
+// `sid`.
+func (queues SemaphoreQueues) Enqueue(sid, vid int) {
+	if _, found := queues[sid]; !found {
+		queues[sid] = make(chan int, 1000) // Always <= 1000 vehicles queued
+	}
+
+	queues[sid] <- vid
+}
+
+// Pops the vehicle from the front of the queue of the semaphore at the end of
+// street whose ID is `sid`.
+// Returns the vehicle ID and true/false depending on whether there was an
+// element do pop or not.
+func (queues SemaphoreQueues) Dequeue(sid int) (int, bool) {
+	if queue, found := queues[sid]; !found || len(queue) == 0 {
+		return -1, false
+	}
+
+	return <-queues[sid], true
+}
+
+// Simulates the solution and returns the Simulation result and the score.
+func (problem *Problem) Simulate(solution Solution) (int, SimulationStatistics) {
+	problem.Validate(solution)
+
+	simulation := make(Simulation)
+	queues := make(SemaphoreQueues)
+
+	stats := SimulationStatistics{
+		jampeaks: make(map[int]int),
+	}
+
+	// Initialize so that at instant 0 each vehicle will be queued at the
+	// semaphore of the first street in their path.
+	for vid := range problem.vehicles {
+		simulation.RegisterArrival(0, Arrival{t: 0, vid: vid, pid: 0})
+	}
+
+	score := 0
+	for now := 0; now < problem.D; now++ {
+		for _, arrival := range simulation.GetArrivals(now) {
+			queues.Enqueue(
+				problem.vehicles[arrival.vid].path[arrival.pid],
+				arrival.vid,
+			)
+		}
+
+		for sid := range problem.streets {
+			if peak, found := stats.jampeaks[sid]; len(queues[sid]) > 0 && (!found || len(queues[sid]) > peak) {
+				stats.jampeaks[sid] = len(queues[sid])
+			}
+		}
+
+		for _, schedule := range solution {
+			sid := schedule.WhichGreen(now)
+			if vid, any := queues.Dequeue(sid); any {
+				// If this was the last street for vehicle vid, update score.
+				// Otherwise, register its arrival to the next intersection.
+				for pid, currsid := range problem.vehicles[vid].path {
+					if currsid == sid {
+						nextsid := problem.vehicles[vid].path[pid+1]
+						if pid == len(problem.vehicles[vid].path)-2 {
+							if now+problem.streets[nextsid].L <= problem.D {
+								score += problem.F + (problem.D - now - problem.streets[nextsid].L)
+							}
+						} else if now+problem.streets[nextsid].L < problem.D {
+							simulation.RegisterArrival(
+								now+problem.streets[nextsid].L,
+								Arrival{
+									t:   now + problem.streets[nextsid].L,
+									vid: vid,
+									pid: pid + 1,
+								},
+							)
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return score, stats
+}
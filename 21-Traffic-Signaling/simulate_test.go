@@ -0,0 +1,177 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Parses a problem from its textual description.
+func parseString(t *testing.T, input string) Problem {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "in.txt")
+	if err := ioutil.WriteFile(filename, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return Parse(filename)
+}
+
+// Imports a solution from its textual description.
+func importString(t *testing.T, problem Problem, output string) Solution {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "out.txt")
+	if err := ioutil.WriteFile(filename, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return problem.Import(filename)
+}
+
+func TestSimulateDatasetA(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	tests := []struct {
+		name     string
+		solution string
+		score    int
+	}{
+		{
+			// The submission described in the problem statement: car 1
+			// arrives at t=4 and scores 1000 + (6 - 4), while car 0 would
+			// only reach its destination at t=7.
+			name:     "statement example",
+			solution: "3\n1\n2\nrue-d-athenes 2\nrue-d-amsterdam 1\n0\n1\nrue-de-londres 2\n2\n1\nrue-de-moscou 1\n",
+			score:    1002,
+		},
+		{
+			name:     "one second each",
+			solution: "3\n1\n2\nrue-d-athenes 1\nrue-d-amsterdam 1\n0\n1\nrue-de-londres 1\n2\n1\nrue-de-moscou 1\n",
+			score:    2002,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			solution := importString(t, problem, test.solution)
+			if score, _ := problem.Simulate(solution); score != test.score {
+				t.Errorf("got score %d, want %d", score, test.score)
+			}
+		})
+	}
+}
+
+func TestSimulateMethodA(t *testing.T) {
+	problem := Parse("in/a.txt")
+
+	if score, _ := problem.Simulate(problem.Solve(MethodA)); score != 2002 {
+		t.Errorf("got score %d, want %d", score, 2002)
+	}
+}
+
+// A tiny city: street "a" enters intersection 1, where it competes with street
+// "c", and street "b" leaves it. Every vehicle drives "a" then "b", which takes
+// 3 seconds.
+const microCity = "0 1 a 1\n1 2 b 3\n2 1 c 1\n"
+
+func TestSimulateMicro(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		solution Solution
+		score    int
+	}{
+		{
+			name:     "bonus plus remaining seconds",
+			input:    "6 3 3 1 10\n" + microCity + "2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}},
+			score:    10 + (6 - 3),
+		},
+		{
+			name:     "arriving exactly at D still scores the bonus",
+			input:    "3 3 3 1 10\n" + microCity + "2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}},
+			score:    10,
+		},
+		{
+			name:     "arriving after D scores nothing",
+			input:    "2 3 3 1 10\n" + microCity + "2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}},
+			score:    0,
+		},
+		{
+			name:     "one vehicle per second crosses a green light",
+			input:    "6 3 3 3 10\n" + microCity + "2 a b\n2 a b\n2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}},
+			score:    (10 + 3) + (10 + 2) + (10 + 1),
+		},
+		{
+			name:     "queued vehicles wait for the next cycle",
+			input:    "6 3 3 3 10\n" + microCity + "2 a b\n2 a b\n2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{0, 2}, tgreens: []int{1, 2}}},
+			score:    (10 + 3) + 10,
+		},
+		{
+			name:     "red light until the first green",
+			input:    "6 3 3 1 10\n" + microCity + "2 a b\n",
+			solution: Solution{1: {id: 1, streets: []int{2, 0}, tgreens: []int{2, 1}}},
+			score:    10 + 1,
+		},
+		{
+			name:     "schedule as long as D",
+			input:    "6 3 3 2 10\n" + microCity + "2 a b\n2 c b\n",
+			solution: Solution{1: {id: 1, streets: []int{0, 2}, tgreens: []int{5, 1}}},
+			score:    10 + 3,
+		},
+		{
+			name:     "intermediate streets",
+			input:    "8 4 4 1 10\n" + microCity + "2 3 d 2\n3 a b d\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}, 2: {id: 2, streets: []int{1}, tgreens: []int{1}}},
+			score:    10 + (8 - 5),
+		},
+		{
+			name:     "intermediate arrival at D never crosses",
+			input:    "3 4 4 1 10\n" + microCity + "2 3 d 2\n3 a b d\n",
+			solution: Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}, 2: {id: 2, streets: []int{1}, tgreens: []int{1}}},
+			score:    0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := parseString(t, test.input)
+			if score, _ := problem.Simulate(test.solution); score != test.score {
+				t.Errorf("got score %d, want %d", score, test.score)
+			}
+		})
+	}
+}
+
+func TestSimulateJamPeaks(t *testing.T) {
+	problem := parseString(t, "6 3 3 3 10\n"+microCity+"2 a b\n2 a b\n2 a b\n")
+	solution := Solution{1: {id: 1, streets: []int{0}, tgreens: []int{1}}}
+
+	_, stats := problem.Simulate(solution)
+	if peak := stats.jampeaks[0]; peak != 3 {
+		t.Errorf("got jam peak %d on street a, want %d", peak, 3)
+	}
+
+	if _, found := stats.jampeaks[1]; found {
+		t.Errorf("got jam peak on street b, want none")
+	}
+}
+
+func TestSimulateScheduleLongerThanD(t *testing.T) {
+	problem := parseString(t, "6 3 3 1 10\n"+microCity+"2 a b\n")
+	solution := Solution{1: {id: 1, streets: []int{0, 2}, tgreens: []int{5, 2}}}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("schedule of %d seconds accepted with D=%d", solution[1].Duration(), problem.D)
+		}
+	}()
+
+	problem.Simulate(solution)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Formats the solution in the output format.
+func (problem *Problem) Format(solution Solution) []byte {
+	var sb strings.Builder
+	sb.WriteString(strconv.FormatInt(int64(len(solution)), 10) + "\n")
+
+	for intersection, schedule := range solution {
+		sb.WriteString(strconv.FormatInt(int64(intersection), 10) + "\n" + strconv.FormatInt(int64(len(schedule.streets)), 10) + "\n")
+		for i, sid := range schedule.streets {
+			sb.WriteString(problem.streets[sid].name + " " + strconv.FormatInt(int64(schedule.tgreens[i]), 10) + "\n")
+		}
+	}
+
+	return []byte(sb.String())
+}
+
+// Exports the solution to a file.
+func (problem *Problem) Export(solution Solution, filename string) {
+	if err := ioutil.WriteFile(filename, problem.Format(solution), 0644); err != nil {
+		panic(err)
+	}
+}
+
+// Imports a solution from a file.
+func (problem *Problem) Import(filename string) Solution {
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	solution := make(Solution)
+
+	lines := strings.Split(string(input), "\n")
+	header, solutionset := lines[0], lines[1:]
+	var count int
+	if _, err := fmt.Sscanf(header, "%d", &count); err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < count; i++ {
+		var iid int
+		if _, err := fmt.Sscanf(solutionset[0], "%d", &iid); err != nil {
+			panic(err)
+		}
+
+		var size int
+		if _, err := fmt.Sscanf(solutionset[1], "%d", &size); err != nil {
+			panic(err)
+		}
+
+		solution[iid] = Schedule{
+			id:      iid,
+			streets: make([]int, size),
+			tgreens: make([]int, size),
+		}
+
+		for k := 0; k < size; k++ {
+			var streetname string
+			var tgreen int
+			if _, err := fmt.Sscanf(solutionset[2+k], "%s %d", &streetname, &tgreen); err != nil {
+				panic(err)
+			}
+
+			solution[iid].streets[k] = problem.streetids[streetname]
+			solution[iid].tgreens[k] = tgreen
+		}
+
+		solutionset = solutionset[2+size:]
+	}
+
+	return solution
+}
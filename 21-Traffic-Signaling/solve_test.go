@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Two vehicles drive "e" then "a", queueing at intersection 1, and one drives
+// "a" then "b", queueing at intersection 2, where "c" competes with "b".
+const waveCity = "10 5 4 3 10\n" +
+	"3 0 e 1\n0 1 a 1\n1 2 b 2\n4 2 c 1\n" +
+	"2 e a\n2 e a\n2 a b\n"
+
+func TestCorridors(t *testing.T) {
+	problem := parseString(t, waveCity)
+
+	want := map[Corridor]int{
+		{from: 0, to: 1}: 2,
+		{from: 1, to: 2}: 1,
+	}
+	if corridors := problem.Corridors(); !reflect.DeepEqual(corridors, want) {
+		t.Errorf("got %v, want %v", corridors, want)
+	}
+}
+
+func TestGreenWave(t *testing.T) {
+	problem := parseString(t, waveCity)
+
+	// Street "a" turns green at t=0 and "b" is 2 seconds long, so the
+	// vehicles leaving "a" find "b" green only if "c" goes first
+	solution := Solution{
+		0: {id: 0, streets: []int{0}, tgreens: []int{1}},
+		1: {id: 1, streets: []int{1}, tgreens: []int{1}},
+		2: {id: 2, streets: []int{2, 3}, tgreens: []int{1, 2}},
+	}
+
+	want := Solution{
+		0: {id: 0, streets: []int{0}, tgreens: []int{1}},
+		1: {id: 1, streets: []int{1}, tgreens: []int{1}},
+		2: {id: 2, streets: []int{3, 2}, tgreens: []int{2, 1}},
+	}
+	if aligned := problem.GreenWave(solution); !reflect.DeepEqual(aligned, want) {
+		t.Errorf("got %+v, want %+v", aligned, want)
+	}
+}
+
+// GreenWave may only rotate the schedules, never change their cycles.
+func TestGreenWaveKeepsCycles(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solution := problem.TrivialSolve()
+
+	aligned := problem.GreenWave(solution)
+	if len(aligned) != len(solution) {
+		t.Fatalf("got %d schedules, want %d", len(aligned), len(solution))
+	}
+
+	for iid, schedule := range solution {
+		if !isRotation(schedule, aligned[iid]) {
+			t.Errorf("intersection %d: %+v is not a rotation of %+v", iid, aligned[iid], schedule)
+		}
+	}
+}
+
+func TestImproveOffsets(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solution := problem.Solve(MethodA)
+
+	original := make(Solution)
+	for iid, schedule := range solution {
+		original[iid] = schedule.Clone()
+	}
+	score, _ := problem.Simulate(solution)
+
+	improved := problem.ImproveOffsets(context.Background(), solution, 0.05)
+	if iscore, _ := problem.Simulate(improved); iscore < score {
+		t.Errorf("got score %d, want at least %d", iscore, score)
+	}
+
+	for iid, schedule := range original {
+		if !isRotation(schedule, improved[iid]) {
+			t.Errorf("intersection %d: %+v is not a rotation of %+v", iid, improved[iid], schedule)
+		}
+	}
+}
+
+// Once cancelled, the improvements must return the solution at once.
+func TestImproveRandomCancelled(t *testing.T) {
+	problem := Parse("in/a.txt")
+	solution := problem.Solve(MethodA)
+	score, _ := problem.Simulate(solution)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	improved := problem.ImproveRandom(ctx, solution, 3600)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v", elapsed)
+	}
+	if iscore, _ := problem.Simulate(improved); iscore != score {
+		t.Errorf("got score %d, want %d", iscore, score)
+	}
+}
+
+// The datasets start from trivial schedules unless green waves are asked for.
+func TestSolveMethods(t *testing.T) {
+	problem := parseString(t, waveCity)
+
+	if got, want := problem.Solve(MethodB), problem.TrivialSolve(); !reflect.DeepEqual(got, want) {
+		t.Errorf("MethodB: got %+v, want %+v", got, want)
+	}
+
+	if got, want := problem.Solve(MethodGreenWave), problem.GreenWave(problem.TrivialSolve()); !reflect.DeepEqual(got, want) {
+		t.Errorf("MethodGreenWave: got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+type (
+	// Customers who cannot buy the same pizza: customer A conflicts with B if A
+	// likes an ingredient B dislikes, or the other way round. The customers who
+	// buy a pizza are thus an independent set of the graph, and the likes of an
+	// independent set make a pizza they all buy.
+	ConflictGraph struct {
+		Adj        [][]int // Customers conflicting with each customer
+		Impossible []bool  // Whether each customer likes an ingredient they dislike
+	}
+)
+
+func (p Problem) ConflictGraph() ConflictGraph {
+	likers := make([][]int, len(p.Ingredients))
+	dislikers := make([][]int, len(p.Ingredients))
+	for c, customer := range p.Customers {
+		for _, i := range customer.Likes {
+			likers[i] = append(likers[i], c)
+		}
+		for _, i := range customer.Dislikes {
+			dislikers[i] = append(dislikers[i], c)
+		}
+	}
+
+	g := ConflictGraph{
+		Adj:        make([][]int, len(p.Customers)),
+		Impossible: make([]bool, len(p.Customers)),
+	}
+
+	// Customers already adjacent to the one whose edges are being added
+	seen := make([]int, len(p.Customers))
+	for c := range seen {
+		seen[c] = -1
+	}
+
+	for c, customer := range p.Customers {
+		add := func(others []int) {
+			for _, other := range others {
+				if other == c {
+					g.Impossible[c] = true
+				} else if seen[other] != c {
+					seen[other] = c
+					g.Adj[c] = append(g.Adj[c], other)
+				}
+			}
+		}
+
+		for _, i := range customer.Likes {
+			add(dislikers[i])
+		}
+		for _, i := range customer.Dislikes {
+			add(likers[i])
+		}
+	}
+
+	return g
+}
+
+// Returns the pizza with the likes of the given customers.
+func (p Problem) PizzaFor(customers []int) Pizza {
+	pizza := make(Pizza)
+	for _, c := range customers {
+		for _, i := range p.Customers[c].Likes {
+			pizza[i] = true
+		}
+	}
+
+	return pizza
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Variables per line of the objective and of the binaries, as CPLEX caps
+// the length of lines.
+const termsPerLine = 16
+
+var (
+	// A like or dislike constraint, with the ingredient it is about
+	constraintPattern = regexp.MustCompile(`^\s*(?:like|dislike)_\d+_(\S+):.*[-+]\s*(i_\d+)\b`)
+	// An ingredient variable
+	ingredientPattern = regexp.MustCompile(`^i_(\d+)$`)
+	// A variable of a CPLEX XML solution file
+	mstPattern = regexp.MustCompile(`<variable name="([^"]+)"[^>]*value="([^"]+)"`)
+)
+
+// Writes the problem as an integer linear program in CPLEX LP format. Binary
+// variable c_<customer> tells whether the customer buys the pizza and
+// i_<ingredient> whether the ingredient is on it. Each constraint tells why a
+// customer may not buy the pizza, and is named after the customer and the
+// ingredient: like_<customer>_<ingredient> or dislike_<customer>_<ingredient>.
+func (p Problem) WriteLP(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	customers := make([]string, len(p.Customers))
+	for c := range p.Customers {
+		customers[c] = fmt.Sprintf("c_%d", c)
+	}
+
+	ingredients := make([]string, len(p.Ingredients))
+	for i := range p.Ingredients {
+		ingredients[i] = fmt.Sprintf("i_%d", i)
+	}
+
+	fmt.Fprintln(bw, "Maximize")
+	writeTerms(bw, "  obj: ", customers, " + ")
+
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "Subject To")
+	for c, customer := range p.Customers {
+		for _, i := range customer.Likes {
+			fmt.Fprintf(bw, "  like_%d_%s: c_%d - i_%d <= 0\n", c, p.Ingredients[i], c, i)
+		}
+		for _, i := range customer.Dislikes {
+			fmt.Fprintf(bw, "  dislike_%d_%s: c_%d + i_%d <= 1\n", c, p.Ingredients[i], c, i)
+		}
+		fmt.Fprintln(bw)
+	}
+
+	fmt.Fprintln(bw, "Binaries")
+	writeTerms(bw, "  ", customers, " ")
+	writeTerms(bw, "  ", ingredients, " ")
+
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "End")
+
+	return bw.Flush()
+}
+
+// Writes the terms joined by `sep`, a few per line.
+func writeTerms(w io.Writer, prefix string, terms []string, sep string) {
+	for k := 0; k < len(terms); k += termsPerLine {
+		line := strings.Join(terms[k:min(k+termsPerLine, len(terms))], sep)
+		if k+termsPerLine < len(terms) {
+			line += strings.TrimRight(sep, " ")
+		}
+
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+		prefix = strings.Repeat(" ", len(prefix))
+	}
+}
+
+// Reads the ingredient each i_<ingredient> variable of a model stands for, as
+// told by the names of its constraints. Models of the same problem do not
+// always number ingredients the same way, so solutions must be read along with
+// the model they solve.
+func ReadLPVariables(in *bufio.Reader) (map[string]string, error) {
+	names := make(map[string]string)
+
+	for lineno := 1; ; lineno++ {
+		line, err := readLine(in)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to read model: %w", err)
+		}
+
+		match := constraintPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		ingredient, variable := match[1], match[2]
+		if name, found := names[variable]; found && name != ingredient {
+			return nil, fmt.Errorf("line %d: %s is both %s and %s", lineno, variable, name, ingredient)
+		}
+		names[variable] = ingredient
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no like or dislike constraints found")
+	}
+
+	return names, nil
+}
+
+// Reads the ingredients set in a solution file listing a variable and its value
+// on each line, as written by most solvers.
+func ReadLPSol(in *bufio.Reader, names map[string]string) ([]string, error) {
+	values := make(map[string]string)
+
+	for {
+		line, err := readLine(in)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to read solution: %w", err)
+		}
+
+		if fields := strings.Fields(line); len(fields) == 2 {
+			values[fields[0]] = fields[1]
+		}
+	}
+
+	return ingredientsSet(values, names)
+}
+
+// Reads the ingredients set in a CPLEX XML solution or MIP start file.
+func ReadMST(in *bufio.Reader, names map[string]string) ([]string, error) {
+	input, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read solution: %w", err)
+	}
+
+	values := make(map[string]string)
+	for _, match := range mstPattern.FindAllStringSubmatch(string(input), -1) {
+		values[match[1]] = match[2]
+	}
+
+	return ingredientsSet(values, names)
+}
+
+// Returns the ingredients whose variables are set, in the order of the model.
+func ingredientsSet(values map[string]string, names map[string]string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no variables found")
+	}
+
+	var set []int
+	for variable, value := range values {
+		match := ingredientPattern.FindStringSubmatch(variable)
+		if match == nil {
+			continue
+		}
+
+		if _, found := names[variable]; !found {
+			return nil, fmt.Errorf("variable %s is not in the model", variable)
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value of %s: %w", variable, err)
+		}
+
+		if v >= 0.5 {
+			i, _ := strconv.Atoi(match[1])
+			set = append(set, i)
+		}
+	}
+	sort.Ints(set)
+
+	ingredients := make([]string, len(set))
+	for k, i := range set {
+		ingredients[k] = names[fmt.Sprintf("i_%d", i)]
+	}
+
+	return ingredients, nil
+}
+
+// Reads the customers set in a CPLEX XML solution or MIP start file. Customers
+// are numbered the same way by every model of a problem, so no model is needed.
+func ReadMSTCustomers(in *bufio.Reader) ([]int, error) {
+	input, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read solution: %w", err)
+	}
+
+	var customers []int
+	for _, match := range mstPattern.FindAllStringSubmatch(string(input), -1) {
+		c, err := strconv.Atoi(strings.TrimPrefix(match[1], "c_"))
+		if !strings.HasPrefix(match[1], "c_") || err != nil {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("value of %s: %w", match[1], err)
+		}
+
+		if v >= 0.5 {
+			customers = append(customers, c)
+		}
+	}
+	sort.Ints(customers)
+
+	return customers, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readPizza(t *testing.T, p Problem, model string, read func(*bufio.Reader, map[string]string) ([]string, error), solution string) Pizza {
+	t.Helper()
+
+	names, err := ReadLPVariables(bufio.NewReader(strings.NewReader(model)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ingredients, err := read(bufio.NewReader(strings.NewReader(solution)), names)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pizza, err := p.NewPizza(ingredients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pizza
+}
+
+// The solutions found by the solvers must score as in the README.
+func TestImportDatasets(t *testing.T) {
+	tests := []struct {
+		problem, letter string
+		score           int
+	}{
+		{"in/a_an_example.in.txt", "a", 2},
+		{"in/b_basic.in.txt", "b", 5},
+		{"in/c_coarse.in.txt", "c", 5},
+		{"in/d_difficult.in.txt", "d", 1805},
+	}
+
+	for _, test := range tests {
+		p := loadProblem(test.problem)
+
+		model, err := os.ReadFile(test.letter + ".lp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution, err := os.ReadFile("out/" + test.letter + ".lpsol")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pizza := readPizza(t, *p, string(model), ReadLPSol, string(solution))
+		if score := p.Score(pizza); score != test.score {
+			t.Errorf("%s: got score %d, want %d", test.letter, score, test.score)
+		}
+	}
+}
+
+func TestWriteLP(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	var model bytes.Buffer
+	if err := p.WriteLP(&model); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"  obj: c_0 + c_1 + c_2\n",
+		"  like_0_cheese: c_0 - i_0 <= 0\n",
+		"  dislike_1_pineapple: c_1 + i_3 <= 1\n",
+		"  i_0 i_1 i_2 i_3 i_4 i_5\n",
+	} {
+		if !strings.Contains(model.String(), line) {
+			t.Errorf("model lacks %q:\n%s", line, model.String())
+		}
+	}
+
+	// Solutions of the model written read back with the same ingredients
+	solution := "i_0 1\ni_1 1.000000\ni_2 0\ni_4 1\ni_5 0.9999\n"
+	pizza := readPizza(t, *p, model.String(), ReadLPSol, solution)
+	if want := (Pizza{0: true, 1: true, 4: true, 5: true}); !reflect.DeepEqual(pizza, want) {
+		t.Errorf("got %v, want %v", pizza, want)
+	}
+
+	mst := `<variables>
+   <variable name="c_0" index="0" value="1"/>
+   <variable name="i_0" index="3" value="1"/>
+   <variable name="i_1" index="4" value="-0"/>
+   <variable name="i_2" index="5" value="1"/>
+  </variables>`
+	pizza = readPizza(t, *p, model.String(), ReadMST, mst)
+	if want := (Pizza{0: true, 2: true}); !reflect.DeepEqual(pizza, want) {
+		t.Errorf("got %v, want %v", pizza, want)
+	}
+}
+
+func TestWriteLPWrapsLines(t *testing.T) {
+	p := Problem{Customers: make([]Customer, 40)}
+
+	var model bytes.Buffer
+	if err := p.WriteLP(&model); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(model.String(), "\n") {
+		if len(line) > 255 {
+			t.Errorf("line of %d characters", len(line))
+		}
+	}
+	if !strings.Contains(model.String(), "c_15 +\n") {
+		t.Errorf("objective not wrapped:\n%s", model.String())
+	}
+}
+
+func TestWritePizza(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	var out bytes.Buffer
+	if err := p.WritePizza(&out, Pizza{5: true, 0: true, 1: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "3 cheese peppers tomatoes\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := p.WritePizza(&out, Pizza{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "0\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
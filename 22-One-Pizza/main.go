@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: go run . <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  lp      write the integer linear program of a problem")
+	fmt.Fprintln(os.Stderr, "  import  turn a .lpsol or .mst solution of a program into a submission")
+	fmt.Fprintln(os.Stderr, "  score   score a submission and tell why customers are lost")
+	fmt.Fprintln(os.Stderr, "  search  improve a solution by local search on the customers conflict graph")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "lp":
+		lpMain(os.Args[2:])
+	case "import":
+		importMain(os.Args[2:])
+	case "score":
+		scoreMain(os.Args[2:])
+	case "search":
+		searchMain(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func lpMain(args []string) {
+	cmd := flag.NewFlagSet("lp", flag.ExitOnError)
+	output := cmd.String("o", "", "file to write the program to")
+	cmd.Parse(args)
+
+	if *output == "" || cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . lp -o <program> <problem>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	p := loadProblem(cmd.Arg(0))
+
+	fd, err := os.Create(*output)
+	if err != nil {
+		log.Fatalf("unable to create file: %v", err)
+	}
+
+	if err := p.WriteLP(fd); err != nil {
+		log.Fatalf("write %s: %v", *output, err)
+	}
+	if err := fd.Close(); err != nil {
+		log.Fatalf("write %s: %v", *output, err)
+	}
+
+	log.Printf("program of %d customers and %d ingredients written to %s", len(p.Customers), len(p.Ingredients), *output)
+}
+
+func importMain(args []string) {
+	cmd := flag.NewFlagSet("import", flag.ExitOnError)
+	input := cmd.String("in", "", "problem file")
+	model := cmd.String("lp", "", "program the solution is for, e.g. a.lp")
+	outdir := cmd.String("out", "out", "directory the submission is written to")
+	cmd.Parse(args)
+
+	if *input == "" || *model == "" || cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . import -in <problem> -lp <program> <solution.lpsol|solution.mst>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	p := loadProblem(*input)
+
+	fd, err := os.Open(*model)
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+
+	names, err := ReadLPVariables(bufio.NewReader(fd))
+	fd.Close()
+	if err != nil {
+		log.Fatalln("parse program:", err)
+	}
+
+	fd, err = os.Open(cmd.Arg(0))
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+
+	read := ReadLPSol
+	if filepath.Ext(cmd.Arg(0)) == ".mst" {
+		read = ReadMST
+	}
+
+	ingredients, err := read(bufio.NewReader(fd), names)
+	fd.Close()
+	if err != nil {
+		log.Fatalln("parse solution:", err)
+	}
+
+	pizza, err := p.NewPizza(ingredients)
+	if err != nil {
+		log.Fatalln("invalid solution:", err)
+	}
+
+	writePizza(*p, pizza, filepath.Join(*outdir, outputName(*input)))
+}
+
+func scoreMain(args []string) {
+	cmd := flag.NewFlagSet("score", flag.ExitOnError)
+	input := cmd.String("in", "", "file of the problem the submission is for")
+	cmd.Parse(args)
+
+	if *input == "" || cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . score -in <problem> <submission>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	p := loadProblem(*input)
+
+	fd, err := os.Open(cmd.Arg(0))
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+
+	pizza, err := p.ReadPizza(bufio.NewReader(fd))
+	fd.Close()
+	if err != nil {
+		log.Fatalln("invalid submission:", err)
+	}
+
+	names := func(ids []int) string {
+		tokens := make([]string, len(ids))
+		for k, id := range ids {
+			tokens[k] = p.Ingredients[id]
+		}
+
+		return strings.Join(tokens, " ")
+	}
+
+	losses := p.Losses(pizza)
+	for _, loss := range losses {
+		var reasons []string
+		if len(loss.Missing) > 0 {
+			reasons = append(reasons, "missing "+names(loss.Missing))
+		}
+		if len(loss.Disliked) > 0 {
+			reasons = append(reasons, "disliked "+names(loss.Disliked))
+		}
+
+		fmt.Printf("[-] Customer %d: %s\n", loss.Customer, strings.Join(reasons, "; "))
+	}
+
+	fmt.Printf("[*] Lost customers: %d\n", len(losses))
+	fmt.Printf("[*] Total score: %d/%d\n", len(p.Customers)-len(losses), len(p.Customers))
+}
+
+func searchMain(args []string) {
+	cmd := flag.NewFlagSet("search", flag.ExitOnError)
+	start := cmd.String("start", "", "solution to start from, a submission or a .mst file")
+	maxtime := cmd.Float64("maxtime", 60, "seconds to search for")
+	seed := cmd.Int64("seed", 1, "random seed")
+	outdir := cmd.String("out", "out", "directory improved submissions are written to")
+	cmd.Parse(args)
+
+	if cmd.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: go run . search [-start <solution.out|solution.mst>] [flags] <problem>")
+		cmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	p := loadProblem(cmd.Arg(0))
+	g := p.ConflictGraph()
+
+	var customers []int
+	if *start != "" {
+		customers = loadStart(*p, *start)
+	}
+
+	// Improvements are written at most every few seconds, and only if they beat
+	// the submission already there.
+	filename := filepath.Join(*outdir, outputName(cmd.Arg(0)))
+	record := submissionScore(*p, filename)
+
+	const every = 10 * time.Second
+	var last time.Time
+	var pending []int
+	write := func(best []int) {
+		last, pending = time.Now(), nil
+
+		pizza := p.PizzaFor(best)
+		score := p.Score(pizza)
+		if score <= record {
+			log.Printf("%s: already serves %d customers, %d not written", filename, record, score)
+			return
+		}
+
+		record = score
+		writePizza(*p, pizza, filename)
+	}
+
+	search, err := NewSearch(g, customers, *seed, func(best []int) {
+		if pending = best; time.Since(last) >= every {
+			write(best)
+		}
+	})
+	if err != nil {
+		log.Fatalln("invalid start:", err)
+	}
+	log.Printf("starting from %d customers out of %d", len(search.Best()), len(p.Customers))
+
+	search.Run(*maxtime)
+	if pending != nil {
+		write(pending)
+	}
+}
+
+// Returns the score of the submission in `filename`, or -1 if there is none.
+func submissionScore(p Problem, filename string) int {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return -1
+	}
+	defer fd.Close()
+
+	pizza, err := p.ReadPizza(bufio.NewReader(fd))
+	if err != nil {
+		return -1
+	}
+
+	return p.Score(pizza)
+}
+
+// Returns the customers who buy the pizza of a submission, or those set in a
+// .mst solution.
+func loadStart(p Problem, filename string) []int {
+	fd, err := os.Open(filename)
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+	defer fd.Close()
+
+	if filepath.Ext(filename) == ".mst" {
+		customers, err := ReadMSTCustomers(bufio.NewReader(fd))
+		if err != nil {
+			log.Fatalln("parse solution:", err)
+		}
+
+		return customers
+	}
+
+	pizza, err := p.ReadPizza(bufio.NewReader(fd))
+	if err != nil {
+		log.Fatalln("parse solution:", err)
+	}
+
+	var customers []int
+	for c, customer := range p.Customers {
+		if customer.Buys(pizza) {
+			customers = append(customers, c)
+		}
+	}
+
+	return customers
+}
+
+func loadProblem(filename string) *Problem {
+	fd, err := os.Open(filename)
+	if err != nil {
+		log.Fatalf("unable to open file: %v", err)
+	}
+	defer fd.Close()
+
+	p, err := LoadProblem(bufio.NewReader(fd))
+	if err != nil {
+		log.Fatalf("parse problem %s: %v", filename, err)
+	}
+
+	return p
+}
+
+// Returns the name of the submission for a problem file, e.g. "a.out" for
+// "in/a_an_example.in.txt".
+func outputName(filename string) string {
+	letter, _, _ := strings.Cut(filepath.Base(filename), "_")
+	return letter + ".out"
+}
+
+// Writes the pizza to `filename` and logs its score.
+func writePizza(p Problem, pizza Pizza, filename string) {
+	fd, err := os.Create(filename)
+	if err != nil {
+		log.Fatalf("unable to create file: %v", err)
+	}
+
+	if err := p.WritePizza(fd, pizza); err != nil {
+		log.Fatalf("write %s: %v", filename, err)
+	}
+	if err := fd.Close(); err != nil {
+		log.Fatalf("write %s: %v", filename, err)
+	}
+
+	log.Printf("%s: %d customers out of %d, %d ingredients", filename, p.Score(pizza), len(p.Customers), len(pizza))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type (
+	Problem struct {
+		Customers   []Customer
+		Ingredients []string       // Names of the ingredients, by order of appearance
+		IDs         map[string]int // IDs of the ingredients by name
+	}
+
+	Customer struct {
+		Likes    []int // IDs of the ingredients the customer wants on the pizza
+		Dislikes []int // IDs of the ingredients the customer wants off the pizza
+	}
+)
+
+func LoadProblem(in *bufio.Reader) (*Problem, error) {
+	p := &Problem{
+		IDs: make(map[string]int),
+	}
+
+	line, err := readLine(in)
+	if err != nil {
+		return p, fmt.Errorf("unable to read problem: %w", err)
+	}
+
+	C, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		return p, fmt.Errorf("invalid number of customers %q: %w", line, err)
+	}
+
+	for i := 0; i < C; i++ {
+		likes, err := p.readIngredients(in)
+		if err != nil {
+			return p, fmt.Errorf("unable to read likes of customer #%d: %w", i, err)
+		}
+
+		dislikes, err := p.readIngredients(in)
+		if err != nil {
+			return p, fmt.Errorf("unable to read dislikes of customer #%d: %w", i, err)
+		}
+
+		p.Customers = append(p.Customers, Customer{
+			Likes:    likes,
+			Dislikes: dislikes,
+		})
+	}
+
+	return p, nil
+}
+
+// Reads a list of ingredients, giving an ID to the ones never seen before.
+func (p *Problem) readIngredients(in *bufio.Reader) ([]int, error) {
+	line, err := readLine(in)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty line")
+	}
+
+	n, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid number of ingredients %q: %w", tokens[0], err)
+	}
+
+	if len(tokens) != n+1 {
+		return nil, fmt.Errorf("got %d ingredients, want %d", len(tokens)-1, n)
+	}
+
+	ids := make([]int, n)
+	for k, name := range tokens[1:] {
+		id, found := p.IDs[name]
+		if !found {
+			id = len(p.Ingredients)
+			p.IDs[name] = id
+			p.Ingredients = append(p.Ingredients, name)
+		}
+
+		ids[k] = id
+	}
+
+	return ids, nil
+}
+
+// Reads a line, the last one of the file possibly lacking its newline.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err == io.EOF && strings.TrimSpace(line) != "" {
+		err = nil
+	}
+
+	return line, err
+}
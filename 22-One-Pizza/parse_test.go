@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadProblem(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	want := &Problem{
+		Customers: []Customer{
+			{Likes: []int{0, 1}, Dislikes: []int{}},
+			{Likes: []int{2}, Dislikes: []int{3}},
+			{Likes: []int{4, 5}, Dislikes: []int{2}},
+		},
+		Ingredients: []string{"cheese", "peppers", "basil", "pineapple", "mushrooms", "tomatoes"},
+		IDs:         map[string]int{"cheese": 0, "peppers": 1, "basil": 2, "pineapple": 3, "mushrooms": 4, "tomatoes": 5},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+
+	for _, input := range []string{"2\n1 a\n0\n", "1\n2 a\n0\n", "x\n"} {
+		if _, err := LoadProblem(bufio.NewReader(strings.NewReader(input))); err == nil {
+			t.Errorf("%q: got no error", input)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	tests := []struct {
+		ingredients []string
+		score       int
+	}{
+		{[]string{"cheese", "peppers", "mushrooms", "tomatoes"}, 2},
+		{[]string{"cheese", "peppers", "basil", "mushrooms", "tomatoes"}, 2},
+		{[]string{"basil"}, 1},
+		{nil, 0},
+	}
+
+	for _, test := range tests {
+		pizza, err := p.NewPizza(test.ingredients)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if score := p.Score(pizza); score != test.score {
+			t.Errorf("%v: got score %d, want %d", test.ingredients, score, test.score)
+		}
+	}
+
+	if _, err := p.NewPizza([]string{"cheese", "ham"}); err == nil {
+		t.Error("unknown ingredient: got no error")
+	}
+}
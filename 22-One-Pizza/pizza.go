@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// IDs of the ingredients on the pizza
+	Pizza map[int]bool
+)
+
+// Returns the pizza with the given ingredients, which must all be known.
+func (p Problem) NewPizza(names []string) (Pizza, error) {
+	pizza := make(Pizza)
+	for _, name := range names {
+		id, found := p.IDs[name]
+		if !found {
+			return nil, fmt.Errorf("no customer mentions ingredient %q", name)
+		}
+
+		pizza[id] = true
+	}
+
+	return pizza, nil
+}
+
+// Reads a pizza in the output format.
+func (p Problem) ReadPizza(in *bufio.Reader) (Pizza, error) {
+	line, err := readLine(in)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pizza: %w", err)
+	}
+
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty pizza line")
+	}
+
+	n, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid number of ingredients %q: %w", tokens[0], err)
+	}
+
+	if len(tokens) != n+1 {
+		return nil, fmt.Errorf("got %d ingredients, want %d", len(tokens)-1, n)
+	}
+
+	return p.NewPizza(tokens[1:])
+}
+
+// Returns true if the customer would buy the pizza.
+func (c Customer) Buys(pizza Pizza) bool {
+	for _, id := range c.Likes {
+		if !pizza[id] {
+			return false
+		}
+	}
+
+	for _, id := range c.Dislikes {
+		if pizza[id] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the number of customers who would buy the pizza.
+func (p Problem) Score(pizza Pizza) int {
+	score := 0
+	for _, c := range p.Customers {
+		if c.Buys(pizza) {
+			score++
+		}
+	}
+
+	return score
+}
+
+// Writes the pizza in the output format, its ingredients in order of
+// appearance in the problem.
+func (p Problem) WritePizza(w io.Writer, pizza Pizza) error {
+	ids := make([]int, 0, len(pizza))
+	for id, on := range pizza {
+		if on {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	tokens := []string{strconv.Itoa(len(ids))}
+	for _, id := range ids {
+		tokens = append(tokens, p.Ingredients[id])
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(tokens, " "))
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+type (
+	// Why a customer does not buy a pizza
+	Loss struct {
+		Customer int
+		Missing  []int // Liked ingredients not on the pizza
+		Disliked []int // Disliked ingredients on the pizza
+	}
+)
+
+// Returns why each customer who would not buy the pizza is lost, in order of
+// customer.
+func (p Problem) Losses(pizza Pizza) []Loss {
+	var losses []Loss
+	for c, customer := range p.Customers {
+		loss := Loss{Customer: c}
+		for _, id := range customer.Likes {
+			if !pizza[id] {
+				loss.Missing = append(loss.Missing, id)
+			}
+		}
+		for _, id := range customer.Dislikes {
+			if pizza[id] {
+				loss.Disliked = append(loss.Disliked, id)
+			}
+		}
+
+		if len(loss.Missing) > 0 || len(loss.Disliked) > 0 {
+			losses = append(losses, loss)
+		}
+	}
+
+	return losses
+}
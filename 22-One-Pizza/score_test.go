@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLosses(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	pizza, err := p.NewPizza([]string{"cheese", "basil", "mushrooms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Loss{
+		{Customer: 0, Missing: []int{p.IDs["peppers"]}},
+		{Customer: 2, Missing: []int{p.IDs["tomatoes"]}, Disliked: []int{p.IDs["basil"]}},
+	}
+	if losses := p.Losses(pizza); !reflect.DeepEqual(losses, want) {
+		t.Errorf("got %+v, want %+v", losses, want)
+	}
+}
+
+// The submissions must score as in the README.
+func TestScoreSubmissions(t *testing.T) {
+	tests := []struct {
+		problem, submission string
+		score               int
+	}{
+		{"in/a_an_example.in.txt", "out/a.out", 2},
+		{"in/b_basic.in.txt", "out/b.out", 5},
+		{"in/c_coarse.in.txt", "out/c.out", 5},
+		{"in/d_difficult.in.txt", "out/d.out", 1805},
+		{"in/e_elaborate.in.txt", "out/e.out", 2051},
+	}
+
+	for _, test := range tests {
+		p := loadProblem(test.problem)
+
+		fd, err := os.Open(test.submission)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pizza, err := p.ReadPizza(bufio.NewReader(fd))
+		fd.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", test.submission, err)
+		}
+
+		if score := len(p.Customers) - len(p.Losses(pizza)); score != test.score {
+			t.Errorf("%s: got score %d, want %d", test.submission, score, test.score)
+		}
+		if score := p.Score(pizza); score != test.score {
+			t.Errorf("%s: got Score %d, want %d", test.submission, score, test.score)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+type (
+	// Iterated local search of a large independent set of the conflict graph,
+	// after Andrade, Resende and Werneck: the set is grown to a maximal one,
+	// improved by (1,2)-swaps, which drop a customer for two others, and then
+	// perturbed by forcing a customer in, the customers it conflicts with being
+	// kept out for a while.
+	Search struct {
+		graph ConflictGraph
+		rng   *rand.Rand
+
+		in      []bool
+		tight   []int // Customers of the set each customer conflicts with
+		tabu    []int // Iteration until which each customer is kept out of the set
+		mark    []int // Stamp of the last candidate of swap12 each customer conflicts with
+		stamp   int
+		set     list // Customers in the set
+		free    list // Customers out of the set not conflicting with it
+		iter    int
+		best    []int
+		improve func(customers []int) // Called with every new best set
+	}
+
+	// A set of customers, picked at random in constant time.
+	list struct {
+		items []int
+		pos   []int // Position of each customer in items, -1 if absent
+	}
+)
+
+// Iterations of the search without a new best set before it restarts from the
+// best one.
+const restartAfter = 20000
+
+func newList(n int) list {
+	l := list{pos: make([]int, n)}
+	for c := range l.pos {
+		l.pos[c] = -1
+	}
+
+	return l
+}
+
+func (l *list) add(c int) {
+	if l.pos[c] == -1 {
+		l.pos[c] = len(l.items)
+		l.items = append(l.items, c)
+	}
+}
+
+func (l *list) remove(c int) {
+	if k := l.pos[c]; k != -1 {
+		last := l.items[len(l.items)-1]
+		l.items[k], l.pos[last] = last, k
+		l.items = l.items[:len(l.items)-1]
+		l.pos[c] = -1
+	}
+}
+
+// Returns a search starting from the given customers, those conflicting with
+// the ones before them being left out. Every new best set is passed to
+// `improve`. Returns an error if a customer does not exist.
+func NewSearch(g ConflictGraph, start []int, seed int64, improve func(customers []int)) (*Search, error) {
+	n := len(g.Adj)
+	for _, c := range start {
+		if c < 0 || c >= n {
+			return nil, fmt.Errorf("customer %d does not exist, there are %d", c, n)
+		}
+	}
+
+	s := &Search{
+		graph:   g,
+		rng:     rand.New(rand.NewSource(seed)),
+		in:      make([]bool, n),
+		tight:   make([]int, n),
+		tabu:    make([]int, n),
+		mark:    make([]int, n),
+		set:     newList(n),
+		free:    newList(n),
+		improve: improve,
+	}
+
+	for c := 0; c < n; c++ {
+		if !g.Impossible[c] {
+			s.free.add(c)
+		}
+	}
+
+	for _, c := range start {
+		if s.free.pos[c] != -1 {
+			s.insert(c)
+		}
+	}
+	s.best = s.customers()
+
+	return s, nil
+}
+
+// Returns the customers in the set, in increasing order.
+func (s *Search) customers() []int {
+	customers := append([]int{}, s.set.items...)
+	sort.Ints(customers)
+
+	return customers
+}
+
+// Returns the largest set found.
+func (s *Search) Best() []int {
+	return s.best
+}
+
+func (s *Search) insert(c int) {
+	s.in[c] = true
+	s.set.add(c)
+	s.free.remove(c)
+
+	for _, other := range s.graph.Adj[c] {
+		s.tight[other]++
+		s.free.remove(other)
+	}
+}
+
+func (s *Search) remove(c int) {
+	s.in[c] = false
+	s.set.remove(c)
+	s.free.add(c)
+
+	for _, other := range s.graph.Adj[c] {
+		if s.tight[other]--; s.tight[other] == 0 && !s.graph.Impossible[other] {
+			s.free.add(other)
+		}
+	}
+}
+
+// Inserts free customers, in random order, until none is left but tabu ones.
+func (s *Search) fill() {
+	for {
+		var allowed []int
+		for _, c := range s.free.items {
+			if s.tabu[c] <= s.iter {
+				allowed = append(allowed, c)
+			}
+		}
+
+		if len(allowed) == 0 {
+			return
+		}
+
+		s.insert(allowed[s.rng.Intn(len(allowed))])
+	}
+}
+
+// Looks for a customer of the set that two others, not conflicting with each
+// other nor with the rest of the set, can replace, and performs the swap.
+func (s *Search) swap12() bool {
+	order := s.rng.Perm(len(s.set.items))
+
+	for _, k := range order {
+		x := s.set.items[k]
+
+		var candidates []int
+		for _, u := range s.graph.Adj[x] {
+			if s.tight[u] == 1 && !s.graph.Impossible[u] && s.tabu[u] <= s.iter {
+				candidates = append(candidates, u)
+			}
+		}
+
+		for a, u := range candidates {
+			s.stamp++
+			for _, v := range s.graph.Adj[u] {
+				s.mark[v] = s.stamp
+			}
+
+			for _, w := range candidates[a+1:] {
+				if s.mark[w] != s.stamp {
+					s.remove(x)
+					s.insert(u)
+					s.insert(w)
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// Forces a customer out of the set in, preferring those with few conflicts
+// among a few random ones, and keeps the customers it conflicts with out for a
+// while.
+func (s *Search) perturb() {
+	if len(s.in) == 0 {
+		return
+	}
+
+	c := -1
+	for t := 0; t < 4; t++ {
+		u := s.rng.Intn(len(s.in))
+		if s.in[u] || s.graph.Impossible[u] || s.tabu[u] > s.iter {
+			continue
+		}
+		if c == -1 || s.tight[u] < s.tight[c] {
+			c = u
+		}
+	}
+
+	if c == -1 {
+		return
+	}
+
+	tenure := 5 + s.rng.Intn(10)
+	for _, other := range s.graph.Adj[c] {
+		if s.in[other] {
+			s.remove(other)
+			s.tabu[other] = s.iter + tenure
+		}
+	}
+	s.insert(c)
+}
+
+// Runs the search for `maxtime` seconds and returns the largest set found.
+func (s *Search) Run(maxtime float64) []int {
+	start := time.Now()
+	lastbest := 0
+
+	for ; time.Since(start).Seconds() < maxtime; s.iter++ {
+		s.fill()
+		for s.swap12() {
+			s.fill()
+		}
+
+		if len(s.set.items) > len(s.best) {
+			s.best, lastbest = s.customers(), s.iter
+			log.Println("iteration", s.iter, "- found", len(s.best), "customers")
+			if s.improve != nil {
+				s.improve(s.best)
+			}
+		}
+
+		if s.iter-lastbest > restartAfter {
+			for _, c := range append([]int{}, s.set.items...) {
+				s.remove(c)
+			}
+			for _, c := range s.best {
+				s.insert(c)
+			}
+			lastbest = s.iter
+		}
+
+		s.perturb()
+	}
+
+	return s.best
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Returns true if the customers can buy the same pizza.
+func compatible(a, b Customer) bool {
+	for _, i := range a.Likes {
+		for _, j := range b.Dislikes {
+			if i == j {
+				return false
+			}
+		}
+	}
+
+	for _, i := range a.Dislikes {
+		for _, j := range b.Likes {
+			if i == j {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestConflictGraph(t *testing.T) {
+	for _, filename := range []string{"in/a_an_example.in.txt", "in/c_coarse.in.txt", "in/d_difficult.in.txt"} {
+		p := loadProblem(filename)
+		g := p.ConflictGraph()
+
+		n := len(p.Customers)
+		if n > 2000 {
+			n = 2000
+		}
+
+		for c := 0; c < n; c++ {
+			adjacent := make(map[int]bool)
+			for _, other := range g.Adj[c] {
+				if adjacent[other] {
+					t.Fatalf("%s: customer %d conflicts twice with %d", filename, c, other)
+				}
+				adjacent[other] = true
+			}
+
+			if impossible := !compatible(p.Customers[c], p.Customers[c]); g.Impossible[c] != impossible {
+				t.Errorf("%s: customer %d: got impossible %v, want %v", filename, c, g.Impossible[c], impossible)
+			}
+
+			for other := 0; other < len(p.Customers); other++ {
+				if other != c && adjacent[other] == compatible(p.Customers[c], p.Customers[other]) {
+					t.Fatalf("%s: customers %d and %d: got conflict %v", filename, c, other, adjacent[other])
+				}
+			}
+		}
+	}
+}
+
+func testSearch(t *testing.T, filename string, start []int, maxtime float64, want int) {
+	t.Helper()
+
+	p := loadProblem(filename)
+	g := p.ConflictGraph()
+
+	improved := 0
+	search, err := NewSearch(g, start, 1, func(customers []int) { improved = len(customers) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := search.Run(maxtime)
+
+	if len(best) < want {
+		t.Errorf("%s: got %d customers, want at least %d", filename, len(best), want)
+	}
+	if improved != 0 && improved != len(best) {
+		t.Errorf("%s: last improvement of %d customers, best of %d", filename, improved, len(best))
+	}
+
+	pizza := p.PizzaFor(best)
+	for _, c := range best {
+		if !p.Customers[c].Buys(pizza) {
+			t.Fatalf("%s: customer %d of the set does not buy its pizza", filename, c)
+		}
+	}
+
+	if score := p.Score(pizza); score < len(best) {
+		t.Errorf("%s: got score %d, want at least %d", filename, score, len(best))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testSearch(t, "in/a_an_example.in.txt", nil, 0.1, 2)
+	testSearch(t, "in/b_basic.in.txt", nil, 0.1, 5)
+	testSearch(t, "in/c_coarse.in.txt", nil, 0.1, 5)
+}
+
+// The search must never lose the customers of the solution it starts from.
+func TestSearchFromSubmission(t *testing.T) {
+	p := loadProblem("in/d_difficult.in.txt")
+
+	fd, err := os.Open("out/d.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+
+	pizza, err := p.ReadPizza(bufio.NewReader(fd))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var start []int
+	for c, customer := range p.Customers {
+		if customer.Buys(pizza) {
+			start = append(start, c)
+		}
+	}
+	if len(start) != 1805 {
+		t.Fatalf("got %d customers in out/d.out, want 1805", len(start))
+	}
+
+	testSearch(t, "in/d_difficult.in.txt", start, 1, 1805)
+}
+
+func TestNewSearchUnknownCustomer(t *testing.T) {
+	g := loadProblem("in/a_an_example.in.txt").ConflictGraph()
+
+	for _, start := range [][]int{{0, 3}, {-1}, {1, 2051}} {
+		if _, err := NewSearch(g, start, 1, nil); err == nil {
+			t.Errorf("%v: got no error", start)
+		}
+	}
+}
+
+// Without customers there is nothing to search, but nothing to crash on either.
+func TestSearchNoCustomers(t *testing.T) {
+	search, err := NewSearch(ConflictGraph{}, nil, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if best := search.Run(0.01); len(best) != 0 {
+		t.Errorf("got customers %v, want none", best)
+	}
+}
+
+func TestReadPizza(t *testing.T) {
+	p := loadProblem("in/a_an_example.in.txt")
+
+	pizza, err := p.ReadPizza(bufio.NewReader(strings.NewReader("2 basil cheese")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pizza) != 2 || !pizza[p.IDs["basil"]] || !pizza[p.IDs["cheese"]] {
+		t.Errorf("got %v", pizza)
+	}
+
+	for _, input := range []string{"", "x basil", "2 basil", "1 ham"} {
+		if _, err := p.ReadPizza(bufio.NewReader(strings.NewReader(input))); err == nil {
+			t.Errorf("%q: got no error", input)
+		}
+	}
+}
+
+func TestReadMSTCustomers(t *testing.T) {
+	const mst = `<CPLEXSolution>
+ <variables>
+  <variable name="c_0" index="0" value="1"/>
+  <variable name="c_1" index="1" value="0"/>
+  <variable name="c_2" index="2" value="0.9999999"/>
+  <variable name="i_0" index="3" value="1"/>
+ </variables>
+</CPLEXSolution>
+`
+
+	customers, err := ReadMSTCustomers(bufio.NewReader(strings.NewReader(mst)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 2 || customers[0] != 0 || customers[1] != 2 {
+		t.Errorf("got %v, want [0 2]", customers)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/mcieno/HashCode
+
+go 1.21
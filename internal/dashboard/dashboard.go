@@ -0,0 +1,175 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Size of the score-over-time charts, in pixels.
+const (
+	chartWidth  = 600
+	chartHeight = 150
+)
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 1em; text-align: right; border-bottom: 1px solid #ccc; }
+svg { background: #f8f8f8; border: 1px solid #ccc; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>Dataset</th><th>Score</th><th>Moves tried</th><th>Moves accepted</th><th>Elapsed (s)</th><th>Best solution</th></tr>
+{{range .Datasets}}<tr>
+<td>{{.Event.Dataset}}</td><td>{{.Event.Score}}</td><td>{{.Event.Tried}}</td><td>{{.Event.Accepted}}</td><td>{{printf "%.0f" .Event.Elapsed}}</td>
+<td>{{if .Downloadable}}<a href="/solution/{{.Event.Dataset}}">download</a>{{else}}-{{end}}</td>
+</tr>
+{{end}}</table>
+{{range .Datasets}}
+<h2>{{.Event.Dataset}}</h2>
+<svg width="{{.Width}}" height="{{.Height}}"><polyline fill="none" stroke="#c33" stroke-width="2" points="{{.Points}}"/></svg>
+{{end}}
+</body>
+</html>
+`))
+
+// What the dashboard shows about a single dataset.
+type datasetView struct {
+	Event        Event
+	Downloadable bool
+	Points       string // The score-over-time chart, as SVG polyline points
+	Width        int
+	Height       int
+}
+
+// Returns the handler of the dashboard:
+//   - /               current best scores and score-over-time charts
+//   - /progress.json  the same data, as JSON
+//   - /solution/<ds>  the current best solution of dataset <ds>
+func Handler(title string, progress *Progress) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		datasets := make([]datasetView, 0)
+		for _, event := range progress.Events() {
+			_, downloadable := progress.Solution(event.Dataset)
+			datasets = append(datasets, datasetView{
+				Event:        event,
+				Downloadable: downloadable,
+				Points:       chartPoints(progress.History(event.Dataset)),
+				Width:        chartWidth,
+				Height:       chartHeight,
+			})
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		dashboardTemplate.Execute(w, struct {
+			Title    string
+			Datasets []datasetView
+		}{title, datasets})
+	})
+
+	mux.HandleFunc("/progress.json", func(w http.ResponseWriter, r *http.Request) {
+		type datasetJSON struct {
+			Event
+			History []Sample `json:"history"`
+		}
+
+		datasets := make([]datasetJSON, 0)
+		for _, event := range progress.Events() {
+			datasets = append(datasets, datasetJSON{event, progress.History(event.Dataset)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(datasets)
+	})
+
+	mux.HandleFunc("/solution/", func(w http.ResponseWriter, r *http.Request) {
+		dataset := strings.TrimPrefix(r.URL.Path, "/solution/")
+		solution, found := progress.Solution(dataset)
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", dataset+".txt"))
+		w.Write(solution)
+	})
+
+	return mux
+}
+
+// Serves the dashboard on `addr` (e.g. ":8080") in the background. Returns an
+// error if the address cannot be listened on, e.g. because it is in use. Errors
+// after that are only logged, so as not to stop the optimisations.
+func Serve(addr, title string, progress *Progress) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := http.Serve(listener, Handler(title, progress)); err != nil {
+			log.Println("[!] Dashboard stopped:", err)
+		}
+	}()
+
+	fmt.Println("[*] Dashboard available at", listener.Addr())
+	return nil
+}
+
+// Scales the samples to the chart size, the lowest score at the bottom and the
+// highest at the top.
+func chartPoints(history []Sample) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	minscore, maxscore := history[0].Score, history[0].Score
+	for _, sample := range history {
+		if sample.Score < minscore {
+			minscore = sample.Score
+		}
+		if sample.Score > maxscore {
+			maxscore = sample.Score
+		}
+	}
+
+	maxelapsed := history[len(history)-1].Elapsed
+	if maxelapsed == 0 {
+		maxelapsed = 1
+	}
+
+	scorerange := float64(maxscore - minscore)
+	if scorerange == 0 {
+		scorerange = 1
+	}
+
+	points := make([]string, len(history))
+	for i, sample := range history {
+		x := sample.Elapsed / maxelapsed * chartWidth
+		y := chartHeight - float64(sample.Score-minscore)/scorerange*(chartHeight-10) - 5
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	return strings.Join(points, " ")
+}
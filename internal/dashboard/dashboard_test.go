@@ -0,0 +1,98 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChartPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []Sample
+		points  string
+	}{
+		{"empty", nil, ""},
+		{"single sample", []Sample{{Elapsed: 0, Score: 7}}, "0.0,145.0"},
+		{
+			// The lowest score is 5 pixels above the bottom and the highest
+			// 5 pixels below the top
+			"rising",
+			[]Sample{{Elapsed: 0, Score: 10}, {Elapsed: 5, Score: 20}, {Elapsed: 10, Score: 30}},
+			"0.0,145.0 300.0,75.0 600.0,5.0",
+		},
+	}
+
+	for _, test := range tests {
+		if points := chartPoints(test.history); points != test.points {
+			t.Errorf("%s: got %q, want %q", test.name, points, test.points)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	progress := NewProgress()
+	progress.Track("b", 42, func() []byte { return []byte("1\n2 0 1\n") })
+	progress.Track("c", 7, nil)
+
+	server := httptest.NewServer(Handler("Test", progress))
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return response.StatusCode, string(body)
+	}
+
+	if status, body := get("/"); status != http.StatusOK || !strings.Contains(body, "<h1>Test</h1>") || !strings.Contains(body, `href="/solution/b"`) {
+		t.Errorf("/: got status %d and body %q", status, body)
+	}
+
+	status, body := get("/progress.json")
+	var datasets []struct {
+		Event
+		History []Sample `json:"history"`
+	}
+	if err := json.Unmarshal([]byte(body), &datasets); status != http.StatusOK || err != nil {
+		t.Fatalf("/progress.json: got status %d and error %v", status, err)
+	}
+	if len(datasets) != 2 || datasets[0].Dataset != "b" || datasets[0].Score != 42 || len(datasets[0].History) != 1 {
+		t.Errorf("/progress.json: got %+v", datasets)
+	}
+
+	if status, body := get("/solution/b"); status != http.StatusOK || body != "1\n2 0 1\n" {
+		t.Errorf("/solution/b: got status %d and body %q", status, body)
+	}
+
+	for _, path := range []string{"/solution/c", "/solution/d", "/missing"} {
+		if status, _ := get(path); status != http.StatusNotFound {
+			t.Errorf("%s: got status %d, want %d", path, status, http.StatusNotFound)
+		}
+	}
+}
+
+// Serving on an address in use must fail without stopping the program.
+func TestServeAddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if err := Serve(listener.Addr().String(), "Test", NewProgress()); err == nil {
+		t.Error("got no error serving on an address in use")
+	}
+}
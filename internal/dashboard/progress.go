@@ -0,0 +1,173 @@
+// Package dashboard collects the progress of optimisations running on several
+// datasets and serves it over HTTP.
+package dashboard
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// The state of the optimisation of a dataset at a certain moment.
+type Event struct {
+	Dataset  string  `json:"dataset"`  // Identifier of the dataset being optimised
+	Score    int     `json:"score"`    // Best score found so far
+	Tried    int     `json:"tried"`    // Number of moves tried so far
+	Accepted int     `json:"accepted"` // Number of moves that improved the score
+	Elapsed  float64 `json:"elapsed"`  // Seconds since the optimisation started
+}
+
+// A point of the score-over-time chart of a dataset.
+type Sample struct {
+	Elapsed float64 `json:"elapsed"` // Seconds since the optimisation started
+	Score   int     `json:"score"`   // Best score at that time
+}
+
+// Collects the progress of every dataset being optimised. It is safe for
+// concurrent use, so datasets can be optimised in parallel.
+type Progress struct {
+	mu        sync.Mutex
+	events    map[string]Event    // Latest event by dataset
+	history   map[string][]Sample // Score over time by dataset
+	solutions map[string][]byte   // Latest best solution by dataset, in the output format
+}
+
+// Publishes the progress of the optimisation of a single dataset. All methods
+// are no-ops on a nil Tracker, so optimisers can report unconditionally.
+type Tracker struct {
+	progress     *Progress
+	event        Event
+	start        time.Time // When the optimisation started
+	lastpublish  time.Time // When the progress was last published
+	lastsnapshot time.Time // When the best solution was last published
+}
+
+// Solutions are snapshotted at most this often, as that can be expensive.
+const snapshotInterval = 10 * time.Second
+
+// Keeps the chart of a dataset at most this many samples long.
+const maxSamples = 1000
+
+func NewProgress() *Progress {
+	return &Progress{
+		events:    make(map[string]Event),
+		history:   make(map[string][]Sample),
+		solutions: make(map[string][]byte),
+	}
+}
+
+// Starts tracking the optimisation of `dataset` from a solution scoring
+// `score`, exported by `snapshot`.
+func (progress *Progress) Track(dataset string, score int, snapshot func() []byte) *Tracker {
+	if progress == nil {
+		return nil
+	}
+
+	tracker := &Tracker{
+		progress: progress,
+		event:    Event{Dataset: dataset, Score: score},
+		start:    time.Now(),
+	}
+	tracker.publish(snapshot, true)
+
+	return tracker
+}
+
+// Registers that a move leading to a solution scoring `score` was tried.
+// Whenever the best score improves, `snapshot` is called (no more often than
+// snapshotInterval) to export the solution offered for download.
+func (tracker *Tracker) Move(score int, snapshot func() []byte) {
+	if tracker == nil {
+		return
+	}
+
+	tracker.event.Tried++
+	if score > tracker.event.Score {
+		tracker.event.Accepted++
+		tracker.event.Score = score
+		tracker.publish(snapshot, false)
+	} else if time.Since(tracker.lastpublish) >= time.Second {
+		tracker.publish(nil, false)
+	}
+}
+
+// Registers the final solution of the optimisation.
+func (tracker *Tracker) Done(score int, snapshot func() []byte) {
+	if tracker == nil {
+		return
+	}
+
+	if score > tracker.event.Score {
+		tracker.event.Score = score
+	}
+	tracker.publish(snapshot, true)
+}
+
+func (tracker *Tracker) publish(snapshot func() []byte, force bool) {
+	tracker.lastpublish = time.Now()
+	tracker.event.Elapsed = time.Since(tracker.start).Seconds()
+
+	var solution []byte
+	if snapshot != nil && (force || time.Since(tracker.lastsnapshot) >= snapshotInterval) {
+		solution = snapshot()
+		tracker.lastsnapshot = time.Now()
+	}
+
+	progress := tracker.progress
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	dataset := tracker.event.Dataset
+	progress.events[dataset] = tracker.event
+
+	history := progress.history[dataset]
+	if len(history) == 0 || history[len(history)-1].Score != tracker.event.Score {
+		history = append(history, Sample{Elapsed: tracker.event.Elapsed, Score: tracker.event.Score})
+		if len(history) > maxSamples {
+			// Halve the resolution of the chart, always keeping the latest sample
+			thinned := make([]Sample, 0, maxSamples/2+1)
+			for i := 0; i < len(history)-1; i += 2 {
+				thinned = append(thinned, history[i])
+			}
+			history = append(thinned, history[len(history)-1])
+		}
+		progress.history[dataset] = history
+	}
+
+	if solution != nil {
+		progress.solutions[dataset] = solution
+	}
+}
+
+// Returns the latest event of every dataset, sorted by dataset.
+func (progress *Progress) Events() []Event {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	events := make([]Event, 0, len(progress.events))
+	for _, event := range progress.events {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Dataset < events[j].Dataset
+	})
+
+	return events
+}
+
+// Returns the score over time of a dataset.
+func (progress *Progress) History(dataset string) []Sample {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	return append([]Sample(nil), progress.history[dataset]...)
+}
+
+// Returns the latest best solution of a dataset in the output format, if any.
+func (progress *Progress) Solution(dataset string) ([]byte, bool) {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	solution, found := progress.solutions[dataset]
+	return solution, found
+}
@@ -0,0 +1,116 @@
+package dashboard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	progress := NewProgress()
+
+	snapshots := 0
+	snapshot := func() []byte {
+		snapshots++
+		return []byte("solution")
+	}
+
+	tracker := progress.Track("b", 10, snapshot)
+	tracker.Move(8, snapshot)
+	tracker.Move(12, snapshot)
+	tracker.Move(12, snapshot)
+	tracker.Move(15, snapshot)
+
+	events := progress.Events()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+
+	event := events[0]
+	if event.Dataset != "b" || event.Score != 15 || event.Tried != 4 || event.Accepted != 2 {
+		t.Errorf("got %+v, want score 15 after 4 moves, 2 accepted", event)
+	}
+
+	// Tracking starts with a snapshot, then they are taken at most every
+	// snapshotInterval
+	if snapshots != 1 {
+		t.Errorf("got %d snapshots, want 1", snapshots)
+	}
+
+	tracker.Done(14, snapshot)
+	if snapshots != 2 {
+		t.Errorf("got %d snapshots after Done, want 2", snapshots)
+	}
+
+	if events := progress.Events(); events[0].Score != 15 {
+		t.Errorf("got score %d after a worse final solution, want 15", events[0].Score)
+	}
+
+	var scores []int
+	for _, sample := range progress.History("b") {
+		scores = append(scores, sample.Score)
+	}
+	if want := []int{10, 12, 15}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("got history %v, want %v", scores, want)
+	}
+
+	if solution, found := progress.Solution("b"); !found || string(solution) != "solution" {
+		t.Errorf("got solution %q, %v", solution, found)
+	}
+
+	if _, found := progress.Solution("c"); found {
+		t.Error("got a solution of an untracked dataset")
+	}
+}
+
+// Trackers of a nil Progress are nil and do nothing.
+func TestNilTracker(t *testing.T) {
+	var progress *Progress
+
+	tracker := progress.Track("b", 0, nil)
+	if tracker != nil {
+		t.Fatalf("got tracker %v, want nil", tracker)
+	}
+
+	tracker.Move(1, nil)
+	tracker.Done(2, nil)
+}
+
+func TestEventsSorted(t *testing.T) {
+	progress := NewProgress()
+	for _, dataset := range []string{"e", "b", "d", "c"} {
+		progress.Track(dataset, 0, nil)
+	}
+
+	var datasets []string
+	for _, event := range progress.Events() {
+		datasets = append(datasets, event.Dataset)
+	}
+	if want := []string{"b", "c", "d", "e"}; !reflect.DeepEqual(datasets, want) {
+		t.Errorf("got %v, want %v", datasets, want)
+	}
+}
+
+// Long histories are thinned out, always keeping the first and latest samples.
+func TestHistoryThinned(t *testing.T) {
+	progress := NewProgress()
+	tracker := progress.Track("b", 0, nil)
+
+	for score := 1; score <= 3*maxSamples; score++ {
+		tracker.Move(score, nil)
+	}
+
+	history := progress.History("b")
+	if len(history) > maxSamples {
+		t.Errorf("got %d samples, want at most %d", len(history), maxSamples)
+	}
+
+	if first, last := history[0].Score, history[len(history)-1].Score; first != 0 || last != 3*maxSamples {
+		t.Errorf("got samples from %d to %d, want from 0 to %d", first, last, 3*maxSamples)
+	}
+
+	for i := 1; i < len(history); i++ {
+		if history[i].Score <= history[i-1].Score {
+			t.Fatalf("scores not increasing at sample %d: %d after %d", i, history[i].Score, history[i-1].Score)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package ingredients implements sets of ingredient IDs as bitsets, so that
+// the ingredients of an order can be combined a word at a time.
+package ingredients
+
+import (
+	"math/bits"
+)
+
+// A set of ingredient IDs, stored as a bitset: ingredient i is in the set if
+// bit i%64 of word i/64 is set. Sets of different lengths can be combined, the
+// missing words being empty.
+type Set []uint64
+
+// Adds ingredient `i` to the set, growing it if needed.
+func (set *Set) Add(i int) {
+	for len(*set) <= i/64 {
+		*set = append(*set, 0)
+	}
+
+	(*set)[i/64] |= 1 << uint(i%64)
+}
+
+// Returns true if ingredient `i` is in the set.
+func (set Set) Has(i int) bool {
+	return i/64 < len(set) && set[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Returns the number of ingredients in the set.
+func (set Set) Len() int {
+	n := 0
+	for _, word := range set {
+		n += bits.OnesCount64(word)
+	}
+
+	return n
+}
+
+// Returns the number of ingredients in the union of the set with the given
+// ones, without building the union.
+func (set Set) UnionLen(others ...Set) int {
+	maxlen := len(set)
+	for _, other := range others {
+		if len(other) > maxlen {
+			maxlen = len(other)
+		}
+	}
+
+	n := 0
+	for w := 0; w < maxlen; w++ {
+		var word uint64
+		if w < len(set) {
+			word = set[w]
+		}
+		for _, other := range others {
+			if w < len(other) {
+				word |= other[w]
+			}
+		}
+		n += bits.OnesCount64(word)
+	}
+
+	return n
+}
+
+// Returns the IDs of the ingredients in the set, in increasing order.
+func (set Set) List() []int {
+	list := make([]int, 0, set.Len())
+	for w, word := range set {
+		for word != 0 {
+			list = append(list, w*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+
+	return list
+}
+
+// Removes ingredient `i` from the set.
+func (set Set) Remove(i int) {
+	if i/64 < len(set) {
+		set[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// Returns true if both sets contain the same ingredients.
+func (set Set) Equal(other Set) bool {
+	for w := 0; w < len(set) || w < len(other); w++ {
+		var a, b uint64
+		if w < len(set) {
+			a = set[w]
+		}
+		if w < len(other) {
+			b = other[w]
+		}
+		if a != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the union of the set with the given ones.
+func (set Set) Union(others ...Set) Set {
+	union := append(Set(nil), set...)
+	for _, other := range others {
+		for len(union) < len(other) {
+			union = append(union, 0)
+		}
+		for w, word := range other {
+			union[w] |= word
+		}
+	}
+
+	return union
+}
+
+// Returns the intersection of the set with the given ones.
+func (set Set) Intersection(others ...Set) Set {
+	intersection := append(Set(nil), set...)
+	for _, other := range others {
+		for w := range intersection {
+			if w < len(other) {
+				intersection[w] &= other[w]
+			} else {
+				intersection[w] = 0
+			}
+		}
+	}
+
+	return intersection
+}
+
+// Returns the ingredients of the set that are not in `other`.
+func (set Set) Difference(other Set) Set {
+	difference := append(Set(nil), set...)
+	for w := range difference {
+		if w < len(other) {
+			difference[w] &^= other[w]
+		}
+	}
+
+	return difference
+}
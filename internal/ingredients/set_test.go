@@ -0,0 +1,215 @@
+package ingredients
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+// Builds a set from a list of ingredient IDs.
+func setOf(ids ...int) Set {
+	var set Set
+	for _, i := range ids {
+		set.Add(i)
+	}
+
+	return set
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name         string
+		a, b, c      Set
+		union        []int
+		intersection []int
+		difference   []int // a \ b
+	}{
+		{
+			name:         "empty",
+			union:        []int{},
+			intersection: []int{},
+			difference:   []int{},
+		},
+		{
+			name:         "disjoint",
+			a:            setOf(0, 1),
+			b:            setOf(2),
+			c:            setOf(3),
+			union:        []int{0, 1, 2, 3},
+			intersection: []int{},
+			difference:   []int{0, 1},
+		},
+		{
+			name:         "overlapping",
+			a:            setOf(1, 2, 3),
+			b:            setOf(2, 3, 4),
+			c:            setOf(3, 5),
+			union:        []int{1, 2, 3, 4, 5},
+			intersection: []int{3},
+			difference:   []int{1},
+		},
+		{
+			// Intersection used to ignore the first of the extra sets
+			name:         "first extra set matters",
+			a:            setOf(1, 2),
+			b:            setOf(1),
+			c:            setOf(1, 2),
+			union:        []int{1, 2},
+			intersection: []int{1},
+			difference:   []int{2},
+		},
+		{
+			name:         "different lengths",
+			a:            setOf(1, 200),
+			b:            setOf(1),
+			c:            setOf(1, 64, 200),
+			union:        []int{1, 64, 200},
+			intersection: []int{1},
+			difference:   []int{200},
+		},
+		{
+			name:         "word boundaries",
+			a:            setOf(63, 64, 127, 128),
+			b:            setOf(63, 128),
+			c:            setOf(63, 64, 128),
+			union:        []int{63, 64, 127, 128},
+			intersection: []int{63, 128},
+			difference:   []int{64, 127},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Union(test.b, test.c).List(); !reflect.DeepEqual(got, test.union) {
+				t.Errorf("Union: got %v, want %v", got, test.union)
+			}
+
+			if got := test.a.UnionLen(test.b, test.c); got != len(test.union) {
+				t.Errorf("UnionLen: got %d, want %d", got, len(test.union))
+			}
+
+			if got := test.a.Intersection(test.b, test.c).List(); !reflect.DeepEqual(got, test.intersection) {
+				t.Errorf("Intersection: got %v, want %v", got, test.intersection)
+			}
+
+			if got := test.a.Difference(test.b).List(); !reflect.DeepEqual(got, test.difference) {
+				t.Errorf("Difference: got %v, want %v", got, test.difference)
+			}
+		})
+	}
+}
+
+func TestSetHasRemove(t *testing.T) {
+	set := setOf(3, 70)
+
+	for i, want := range map[int]bool{0: false, 3: true, 70: true, 71: false, 1000: false} {
+		if got := set.Has(i); got != want {
+			t.Errorf("Has(%d): got %v, want %v", i, got, want)
+		}
+	}
+
+	set.Remove(70)
+	set.Remove(1000)
+	if got := set.List(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("after Remove: got %v, want %v", got, []int{3})
+	}
+
+	if !set.Equal(setOf(3)) || set.Equal(setOf(3, 4)) || !setOf().Equal(Set{0, 0}) {
+		t.Errorf("Equal does not ignore trailing empty words")
+	}
+}
+
+// A set of ingredients as a map, used as the reference implementation.
+type referenceSet map[int]bool
+
+// Turns a list of small random numbers into both representations.
+func sets(ids []uint8) (Set, referenceSet) {
+	set, reference := Set(nil), make(referenceSet)
+	for _, i := range ids {
+		set.Add(int(i))
+		reference[int(i)] = true
+	}
+
+	return set, reference
+}
+
+func (reference referenceSet) List() []int {
+	list := make([]int, 0, len(reference))
+	for i := range reference {
+		list = append(list, i)
+	}
+	sort.Ints(list)
+
+	return list
+}
+
+func TestSetProperties(t *testing.T) {
+	config := &quick.Config{Rand: rand.New(rand.NewSource(1))}
+
+	union := func(a, b, c []uint8) bool {
+		sa, ra := sets(a)
+		sb, rb := sets(b)
+		sc, rc := sets(c)
+
+		reference := make(referenceSet)
+		for _, r := range []referenceSet{ra, rb, rc} {
+			for i := range r {
+				reference[i] = true
+			}
+		}
+
+		return reflect.DeepEqual(sa.Union(sb, sc).List(), reference.List()) &&
+			sa.UnionLen(sb, sc) == len(reference)
+	}
+
+	intersection := func(a, b, c []uint8) bool {
+		sa, ra := sets(a)
+		sb, rb := sets(b)
+		sc, rc := sets(c)
+
+		reference := make(referenceSet)
+		for i := range ra {
+			if rb[i] && rc[i] {
+				reference[i] = true
+			}
+		}
+
+		return reflect.DeepEqual(sa.Intersection(sb, sc).List(), reference.List())
+	}
+
+	difference := func(a, b []uint8) bool {
+		sa, ra := sets(a)
+		sb, rb := sets(b)
+
+		reference := make(referenceSet)
+		for i := range ra {
+			if !rb[i] {
+				reference[i] = true
+			}
+		}
+
+		return reflect.DeepEqual(sa.Difference(sb).List(), reference.List())
+	}
+
+	// |A ∪ B| = |A| + |B| - |A ∩ B| and A = (A \ B) ∪ (A ∩ B)
+	identities := func(a, b []uint8) bool {
+		sa, _ := sets(a)
+		sb, _ := sets(b)
+
+		return sa.UnionLen(sb) == sa.Len()+sb.Len()-sa.Intersection(sb).Len() &&
+			sa.Equal(sa.Difference(sb).Union(sa.Intersection(sb)))
+	}
+
+	for name, property := range map[string]interface{}{
+		"union":        union,
+		"intersection": intersection,
+		"difference":   difference,
+		"identities":   identities,
+	} {
+		if err := quick.Check(property, config); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
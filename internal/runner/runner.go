@@ -0,0 +1,196 @@
+// Package runner processes the datasets of a problem concurrently, each within
+// its own time budget, and summarises the scores.
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A dataset to process.
+type Job struct {
+	Dataset string        // Identifier of the dataset, e.g. "b"
+	Title   string        // Name of the dataset in the summary, e.g. "B - small"
+	Input   string        // File of the problem
+	Output  string        // Prefix of the file the solution is written to
+	Start   string        // File of the solution to start from, if any
+	Budget  time.Duration // Time the dataset may take, 0 for the runner's
+}
+
+// The outcome of a job.
+type Result struct {
+	Job     Job
+	Score   int           // Score of the solution found
+	Err     error         // Why no solution was found, if so
+	Elapsed time.Duration // Time the job took
+}
+
+// Processes datasets concurrently.
+type Runner struct {
+	Workers int           // Maximum number of datasets processed at once
+	Budget  time.Duration // Time each dataset may take, 0 for no limit
+}
+
+// Processes the jobs with `process`, at most Workers at a time, and returns
+// their results in the same order. Each job gets a context whose deadline is
+// its budget, which `process` should honour, e.g. with Seconds. Once `ctx` is
+// cancelled no more jobs are started, and those still running see their
+// context cancelled too: `process` should then return what it has found so
+// far, which is reported as usual once it does.
+func (runner Runner) Run(ctx context.Context, jobs []Job, process func(ctx context.Context, job Job) (int, error)) []Result {
+	workers := runner.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(jobs))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range queue {
+				if ctx.Err() != nil {
+					results[k] = Result{Job: jobs[k], Err: ctx.Err()}
+					continue
+				}
+				results[k] = runner.run(ctx, jobs[k], process)
+			}
+		}()
+	}
+
+	for k := range jobs {
+		if ctx.Err() != nil {
+			results[k] = Result{Job: jobs[k], Err: ctx.Err()}
+			continue
+		}
+		queue <- k
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// Processes a single job, turning panics into errors.
+func (runner Runner) run(ctx context.Context, job Job, process func(ctx context.Context, job Job) (int, error)) (result Result) {
+	budget := job.Budget
+	if budget == 0 {
+		budget = runner.Budget
+	}
+
+	jctx, cancel := ctx, context.CancelFunc(func() {})
+	if budget > 0 {
+		jctx, cancel = context.WithTimeout(ctx, budget)
+	}
+	defer cancel()
+
+	start := time.Now()
+	result = Result{Job: job}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("panic: %v", r)
+		}
+		result.Elapsed = time.Since(start)
+	}()
+	result.Score, result.Err = process(jctx, job)
+
+	return result
+}
+
+// Returns the seconds left before the deadline of the context, or practically
+// forever if it has none, to pass to optimisers taking a time budget.
+func Seconds(ctx context.Context) float64 {
+	if deadline, ok := ctx.Deadline(); ok {
+		return math.Max(0, time.Until(deadline).Seconds())
+	}
+
+	return math.MaxInt32
+}
+
+// Parses per-dataset budgets, such as "c=600,e=120" (in seconds).
+func ParseBudgets(spec string) (map[string]time.Duration, error) {
+	budgets := make(map[string]time.Duration)
+	if spec == "" {
+		return budgets, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		k := strings.Index(part, "=")
+		if k <= 0 {
+			return nil, fmt.Errorf("invalid budget %q: want <dataset>=<seconds>", part)
+		}
+
+		var seconds float64
+		if _, err := fmt.Sscanf(part[k+1:], "%g", &seconds); err != nil {
+			return nil, fmt.Errorf("invalid budget %q: %w", part, err)
+		}
+		budgets[part[:k]] = time.Duration(seconds * float64(time.Second))
+	}
+
+	return budgets, nil
+}
+
+// Formats the results as the score tables of the README, in the order of the
+// jobs.
+func Summary(results []Result) string {
+	rows := make([][2]string, 0, len(results))
+	total := 0
+	for _, result := range results {
+		score := commas(result.Score)
+		if result.Err != nil {
+			score = result.Err.Error()
+		} else {
+			total += result.Score
+		}
+		rows = append(rows, [2]string{result.Job.Title, score})
+	}
+
+	// Column widths, leaving a space on either side of the widest cell
+	width := [2]int{len(" DATA SET "), len(" SCORE ")}
+	for _, row := range rows {
+		for c, cell := range row {
+			width[c] = max(width[c], len([]rune(cell))+2)
+		}
+	}
+
+	center := func(text string, n int) string {
+		left := (n - len(text)) / 2
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", n-len(text)-left)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("|%s|%s|\n", center("DATA SET", width[0]), center("SCORE", width[1])))
+	sb.WriteString(fmt.Sprintf("| %s | %s: |\n", strings.Repeat("-", width[0]-2), strings.Repeat("-", width[1]-3)))
+	for _, row := range rows {
+		pad := width[0] - 2 - len([]rune(row[0]))
+		sb.WriteString(fmt.Sprintf("| %s%s | %*s |\n", row[0], strings.Repeat(" ", pad), width[1]-2, row[1]))
+	}
+	sb.WriteString(fmt.Sprintf("\n#### Total score: %s\n", commas(total)))
+
+	return sb.String()
+}
+
+// Formats a number with commas separating the thousands.
+func commas(n int) string {
+	if n < 0 {
+		return "-" + commas(-n)
+	}
+	digits := fmt.Sprint(n)
+
+	var sb strings.Builder
+	for k, digit := range digits {
+		if k > 0 && (len(digits)-k)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(digit)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunnerRun(t *testing.T) {
+	jobs := []Job{{Dataset: "a"}, {Dataset: "b", Budget: 10 * time.Millisecond}, {Dataset: "c"}, {Dataset: "d"}}
+	runner := Runner{Workers: 2, Budget: time.Hour}
+
+	var running, peak int32
+	results := runner.Run(context.Background(), jobs, func(ctx context.Context, job Job) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			if p := atomic.LoadInt32(&peak); n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		switch job.Dataset {
+		case "b":
+			// Per-dataset budgets override the runner's
+			if s := Seconds(ctx); s > 0.01 {
+				return 0, errors.New("budget not applied")
+			}
+			<-ctx.Done()
+		case "c":
+			panic("boom")
+		case "d":
+			return 0, errors.New("failed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return len(job.Dataset), nil
+	})
+
+	if peak > 2 {
+		t.Errorf("got %d jobs at once, want at most 2", peak)
+	}
+
+	want := []struct {
+		score int
+		err   string
+	}{{1, ""}, {1, ""}, {0, "panic: boom"}, {0, "failed"}}
+	for k, result := range results {
+		if result.Job.Dataset != jobs[k].Dataset {
+			t.Errorf("result %d: got dataset %s, want %s", k, result.Job.Dataset, jobs[k].Dataset)
+		}
+
+		err := ""
+		if result.Err != nil {
+			err = result.Err.Error()
+		}
+		if result.Score != want[k].score || err != want[k].err {
+			t.Errorf("result %d: got score %d and error %q, want %d and %q", k, result.Score, err, want[k].score, want[k].err)
+		}
+	}
+}
+
+func TestRunnerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := []Job{{Dataset: "a"}, {Dataset: "b"}, {Dataset: "c"}}
+
+	var started int32
+	results := Runner{Workers: 1}.Run(ctx, jobs, func(ctx context.Context, job Job) (int, error) {
+		atomic.AddInt32(&started, 1)
+		cancel()
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond) // Exports what it has found
+		return 42, nil
+	})
+
+	if n := atomic.LoadInt32(&started); n != 1 {
+		t.Errorf("got %d jobs started, want 1", n)
+	}
+
+	// The running job is waited for, the others never start
+	if results[0].Score != 42 || results[0].Err != nil || results[0].Elapsed < 10*time.Millisecond {
+		t.Errorf("result 0: got score %d, error %v after %v, want 42 and no error", results[0].Score, results[0].Err, results[0].Elapsed)
+	}
+	for k, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %d: got error %v, want %v", k+1, result.Err, context.Canceled)
+		}
+	}
+}
+
+func TestParseBudgets(t *testing.T) {
+	budgets, err := ParseBudgets("c=600,e=0.5")
+	if err != nil || budgets["c"] != 600*time.Second || budgets["e"] != 500*time.Millisecond || len(budgets) != 2 {
+		t.Errorf("got %v, %v", budgets, err)
+	}
+
+	for _, spec := range []string{"c", "=1", "c=x"} {
+		if _, err := ParseBudgets(spec); err == nil {
+			t.Errorf("%q: got no error", spec)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	results := []Result{
+		{Job: Job{Title: "A - example"}, Score: 74},
+		{Job: Job{Title: "B - small"}, Score: 1234567},
+		{Job: Job{Title: "C - medium"}, Err: errors.New("failed")},
+	}
+
+	want := "" +
+		"|  DATA SET   |   SCORE   |\n" +
+		"| ----------- | --------: |\n" +
+		"| A - example |        74 |\n" +
+		"| B - small   | 1,234,567 |\n" +
+		"| C - medium  |    failed |\n" +
+		"\n" +
+		"#### Total score: 1,234,641\n"
+
+	if got := Summary(results); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}